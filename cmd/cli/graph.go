@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/raffis/rageta/internal/pipeline"
+	"github.com/raffis/rageta/internal/run"
+	"github.com/raffis/rageta/internal/runtime"
+	"github.com/spf13/cobra"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [ref]",
+	Short: "Print the execution graph of a pipeline",
+	Long: `The graph command resolves a pipeline and prints its execution graph.
+Nodes are typed by their step type while edges are distinguished by their kind: sequence (and), parallel (concurrent), pipe and needs.
+Inherited pipelines and matrices which do not depend on runtime values can be expanded into the graph.`,
+	Example: `  # Render rageta.yaml from the current directory using graphviz
+  rageta graph | dot -Tsvg > pipeline.svg
+
+  # Print a mermaid flowchart including all inherited pipelines
+  rageta graph ghcr.io/org/pipeline:v1 -o mermaid --expand-inherit`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: graphCmdRun,
+}
+
+type graphFlags struct {
+	output          string
+	expandInherit   bool
+	expandMatrix    bool
+	providerOptions run.ProviderOptions
+}
+
+var graphArgs = newGraphFlags()
+
+func newGraphFlags() graphFlags {
+	return graphFlags{
+		output:          "dot",
+		providerOptions: run.NewProviderOptions(),
+	}
+}
+
+func init() {
+	graphArgs.providerOptions.BindFlags(graphCmd.Flags())
+	graphCmd.Flags().StringVarP(&graphArgs.output, "output", "o", graphArgs.output, "the format of the graph, can be 'dot', 'mermaid' or 'json'")
+	graphCmd.Flags().BoolVar(&graphArgs.expandInherit, "expand-inherit", false, "Resolve inherited pipelines and add their steps to the graph")
+	graphCmd.Flags().BoolVar(&graphArgs.expandMatrix, "expand-matrix", false, "Add a node for each matrix combination which does not depend on runtime values")
+	rootCmd.AddCommand(graphCmd)
+}
+
+func graphCmdRun(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if rootArgs.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rootArgs.timeout)
+		defer cancel()
+	}
+
+	// An empty ref resolves rageta.yaml or rageta.star from the current directory
+	var ref string
+	if len(args) > 0 {
+		ref = args[0]
+	}
+
+	opts := graphArgs.providerOptions
+	opts.DBPath = rootArgs.dbPath
+	opts.CacheDir = rootArgs.cacheDir
+
+	store, persistDB := run.CreateProvider(logger, runtime.PullImagePolicyMissing, opts)
+	defer func() {
+		if err := persistDB(); err != nil {
+			logger.V(1).Error(err, "failed to persist database")
+		}
+	}()
+
+	spec, err := store.Resolve(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	var graphOpts []pipeline.GraphOption
+	if graphArgs.expandInherit {
+		graphOpts = append(graphOpts, pipeline.WithExpandInherit(store))
+	}
+
+	if graphArgs.expandMatrix {
+		graphOpts = append(graphOpts, pipeline.WithExpandMatrix())
+	}
+
+	graph, err := pipeline.NewGraph(ctx, spec, graphOpts...)
+	if err != nil {
+		return err
+	}
+
+	switch graphArgs.output {
+	case "dot":
+		cmd.Print(graph.DOT())
+	case "mermaid":
+		cmd.Print(graph.Mermaid())
+	case "json":
+		marshalled, err := json.MarshalIndent(&graph, "", "  ")
+		if err != nil {
+			return fmt.Errorf("graph JSON conversion failed: %w", err)
+		}
+		marshalled = append(marshalled, "\n"...)
+		cmd.Print(string(marshalled))
+	default:
+		return fmt.Errorf("unsupported output format %q", graphArgs.output)
+	}
+
+	return nil
+}
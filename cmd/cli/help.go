@@ -49,7 +49,11 @@ func printHelpPipeline(cmd *cobra.Command, ref string, full bool) error {
 		defer cancel()
 	}
 
-	store, persistDB := run.CreateProvider(runtime.PullImagePolicyAlways, rootArgs.dbPath, helpArgs.ociOptions)
+	store, persistDB := run.CreateProvider(logger, runtime.PullImagePolicyAlways, run.ProviderOptions{
+		OCI:      helpArgs.ociOptions,
+		DBPath:   rootArgs.dbPath,
+		CacheDir: rootArgs.cacheDir,
+	})
 	command, err := store.Resolve(ctx, ref)
 	if err != nil {
 		return err
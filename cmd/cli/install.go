@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/raffis/rageta/internal/provider"
+	"github.com/raffis/rageta/internal/run"
+	"github.com/raffis/rageta/internal/runtime"
+	"github.com/spf13/cobra"
+)
+
+var installCmd = &cobra.Command{
+	Use:   "install <ref>",
+	Short: "Install a pipeline into the local database",
+	Long: `The install command resolves a remote pipeline reference (OCI, git or http) and stores it in the local database.
+Installed pipelines can be executed offline and may be given a short alias.`,
+	Example: `  # Install a pipeline and run it using an alias
+  rageta install ghcr.io/org/pipelines/build:v1 --alias build
+  rageta run build
+
+  # Install a pipeline from a git repository
+  rageta install git+https://github.com/org/repo//rageta.yaml@v1.0.0`,
+	Args: cobra.ExactArgs(1),
+	RunE: installCmdRun,
+}
+
+type installFlags struct {
+	alias           string
+	providerOptions run.ProviderOptions
+}
+
+var installArgs = newInstallFlags()
+
+func newInstallFlags() installFlags {
+	return installFlags{
+		providerOptions: run.NewProviderOptions(),
+	}
+}
+
+func init() {
+	installArgs.providerOptions.BindFlags(installCmd.Flags())
+	installCmd.Flags().StringVar(&installArgs.alias, "alias", "", "Short name which can be used instead of the full reference, e.g. rageta run <alias>")
+	rootCmd.AddCommand(installCmd)
+}
+
+func installCmdRun(cmd *cobra.Command, args []string) error {
+	ref := args[0]
+	if installArgs.alias != "" && !provider.IsValidAlias(installArgs.alias) {
+		return fmt.Errorf("invalid alias %q: may only contain alphanumeric characters, '-' and '_'", installArgs.alias)
+	}
+
+	ctx := cmd.Context()
+	if rootArgs.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rootArgs.timeout)
+		defer cancel()
+	}
+
+	opts := installArgs.providerOptions
+	opts.DBPath = rootArgs.dbPath
+	opts.CacheDir = rootArgs.cacheDir
+
+	store, persistDB := run.CreateProvider(logger, runtime.PullImagePolicyAlways, opts)
+	if _, err := store.Resolve(ctx, ref); err != nil {
+		return err
+	}
+
+	if err := persistDB(); err != nil {
+		return fmt.Errorf("failed to persist database: %w", err)
+	}
+
+	localDB, err := run.OpenDatabase(rootArgs.dbPath)
+	if err != nil {
+		return err
+	}
+
+	if _, err := localDB.Get(ref); err != nil {
+		return fmt.Errorf("%q is not a remote pipeline reference and can not be installed", ref)
+	}
+
+	if installArgs.alias != "" {
+		if err := localDB.SetAlias(ref, installArgs.alias); err != nil {
+			return err
+		}
+
+		if err := run.PersistDatabase(rootArgs.dbPath, localDB); err != nil {
+			return fmt.Errorf("failed to persist database: %w", err)
+		}
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "installed %s\n", ref)
+	return nil
+}
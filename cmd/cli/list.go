@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/raffis/rageta/internal/run"
+	"github.com/raffis/rageta/pkg/apis/package/v1beta1"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List pipelines from the local database",
+	Example: `  # List installed pipelines
+  rageta list`,
+	Args: cobra.NoArgs,
+	RunE: listCmdRun,
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+}
+
+func listCmdRun(cmd *cobra.Command, args []string) error {
+	localDB, err := run.OpenDatabase(rootArgs.dbPath)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tALIAS\tSOURCE\tDIGEST\tINSTALLED")
+
+	for _, app := range localDB.List() {
+		installedAt := ""
+		if !app.InstalledAt.IsZero() {
+			installedAt = app.InstalledAt.Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", app.Name, app.Alias, app.Source, appVersion(app), installedAt)
+	}
+
+	return w.Flush()
+}
+
+// appVersion returns the artifact digest or for pipelines from git or http their source revision.
+func appVersion(app v1beta1.App) string {
+	if app.Digest != "" {
+		return app.Digest
+	}
+
+	return app.Revision
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/raffis/rageta/internal/ocisetup"
+	"github.com/raffis/rageta/internal/provider"
+	"github.com/raffis/rageta/internal/run"
+	"github.com/spf13/cobra"
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "Show installed pipelines with newer matching versions",
+	Long: `The outdated command lists all pipelines from the local database which were installed using a semver range reference
+(for example ghcr.io/org/pipeline@^1.4) and for which the registry holds a newer version matching the range.`,
+	Example: `  # Show outdated pipelines
+  rageta outdated`,
+	RunE: outdatedCmdRun,
+}
+
+type outdatedFlags struct {
+	ociOptions *ocisetup.Options
+}
+
+var outdatedArgs = newOutdatedFlags()
+
+func newOutdatedFlags() outdatedFlags {
+	return outdatedFlags{
+		ociOptions: ocisetup.DefaultOptions(),
+	}
+}
+
+func init() {
+	outdatedArgs.ociOptions.BindFlags(outdatedCmd.Flags())
+	rootCmd.AddCommand(outdatedCmd)
+}
+
+func outdatedCmdRun(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if rootArgs.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rootArgs.timeout)
+		defer cancel()
+	}
+
+	localDB, err := run.OpenDatabase(rootArgs.dbPath)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCURRENT\tLATEST")
+
+	for _, app := range localDB.List() {
+		versionRef, ok := provider.ParseVersionRef(app.Name)
+		if !ok {
+			continue
+		}
+
+		outdatedArgs.ociOptions.URL = versionRef.Repository
+		remoteOpts, err := outdatedArgs.ociOptions.RemoteOptions(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to build registry options: %w", err)
+		}
+
+		latest, err := versionRef.Resolve(ctx, provider.NewRegistryTagLister(remoteOpts...))
+		if err != nil {
+			logger.V(1).Error(err, "failed to lookup latest version", "pipeline", app.Name)
+			continue
+		}
+
+		if latest != app.Tag {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", app.Name, app.Tag, latest)
+		}
+	}
+
+	return w.Flush()
+}
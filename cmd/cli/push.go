@@ -109,16 +109,16 @@ func newPushFlags() pushFlags {
 }
 
 func init() {
-	pushCmd.Flags().StringVarP(&pushArgs.path, "path", "f", "", "path to the directory where the Kubernetes manifests are located")
-	pushCmd.Flags().StringVar(&pushArgs.source, "source", "", "the source address, e.g. the Git URL")
-	pushCmd.Flags().StringVar(&pushArgs.revision, "revision", "", "the source revision in the format '<branch|tag>@sha1:<commit-sha>'")
-	pushCmd.Flags().StringArrayVarP(&pushArgs.tags, "tags", "t", nil, "Push additional tags")
-	pushCmd.Flags().StringArrayVarP(&pushArgs.annotations, "annotations", "a", nil, "Set custom OCI annotations in the format '<key>=<value>'")
-	pushCmd.Flags().StringVarP(&pushArgs.output, "output", "o", "",
+	pushCmd.PersistentFlags().StringVarP(&pushArgs.path, "path", "f", "", "path to the directory where the Kubernetes manifests are located")
+	pushCmd.PersistentFlags().StringVar(&pushArgs.source, "source", "", "the source address, e.g. the Git URL")
+	pushCmd.PersistentFlags().StringVar(&pushArgs.revision, "revision", "", "the source revision in the format '<branch|tag>@sha1:<commit-sha>'")
+	pushCmd.PersistentFlags().StringArrayVarP(&pushArgs.tags, "tags", "t", nil, "Push additional tags")
+	pushCmd.PersistentFlags().StringArrayVarP(&pushArgs.annotations, "annotations", "a", nil, "Set custom OCI annotations in the format '<key>=<value>'")
+	pushCmd.PersistentFlags().StringVarP(&pushArgs.output, "output", "o", "",
 		"the format in which the artifact digest should be printed, can be 'json' or 'yaml'")
-	pushCmd.Flags().BoolVarP(&pushArgs.debug, "debug", "", false, "display logs from underlying library")
+	pushCmd.PersistentFlags().BoolVarP(&pushArgs.debug, "debug", "", false, "display logs from underlying library")
 
-	pushArgs.ociOptions.BindFlags(pushCmd.Flags())
+	pushArgs.ociOptions.BindFlags(pushCmd.PersistentFlags())
 	rootCmd.AddCommand(pushCmd)
 	oci.CanonicalConfigMediaType = "application/rageta"
 }
@@ -137,7 +137,7 @@ func prepareOCIFile(imgFlags imgFlags, args []string) (string, error) {
 
 	// Handle stdin input
 	if imgFlags.path == "-" || imgFlags.path == "/dev/stdin" {
-		path, err = saveReaderToFile(os.Stdin)
+		path, err = saveReaderToFile(os.Stdin, "main.yaml")
 		if err != nil {
 			return "", err
 		}
@@ -157,7 +157,13 @@ func prepareOCIFile(imgFlags imgFlags, args []string) (string, error) {
 			_ = f.Close()
 		}()
 
-		path, err = saveReaderToFile(f)
+		// Starlark definitions are evaluated when the artifact is pulled
+		manifestName := "main.yaml"
+		if filepath.Ext(path) == ".star" {
+			manifestName = "main.star"
+		}
+
+		path, err = saveReaderToFile(f, manifestName)
 		if err != nil {
 			return "", err
 		}
@@ -167,6 +173,11 @@ func prepareOCIFile(imgFlags imgFlags, args []string) (string, error) {
 }
 
 func pushCmdRun(cmd *cobra.Command, args []string) error {
+	return pushArtifact(cmd, args, nil)
+}
+
+// pushArtifact pushes the manifests to the registry, the prepared manifest path is checked by validate if given.
+func pushArtifact(cmd *cobra.Command, args []string, validate func(path string) error) error {
 	if pushArgs.source == "" {
 		return fmt.Errorf("--source is required")
 	}
@@ -189,6 +200,12 @@ func pushCmdRun(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
+	if validate != nil {
+		if err := validate(path); err != nil {
+			return err
+		}
+	}
+
 	ociURL := args[0]
 
 	ctx := cmd.Context()
@@ -286,7 +303,7 @@ func pushCmdRun(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func saveReaderToFile(reader io.Reader) (string, error) {
+func saveReaderToFile(reader io.Reader, manifestName string) (string, error) {
 	b, err := io.ReadAll(bufio.NewReader(reader))
 	if err != nil {
 		return "", err
@@ -297,7 +314,7 @@ func saveReaderToFile(reader io.Reader) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	f, err := os.OpenFile(filepath.Join(tmpDir, "main.yaml"), os.O_CREATE|os.O_RDWR, 0644)
+	f, err := os.OpenFile(filepath.Join(tmpDir, manifestName), os.O_CREATE|os.O_RDWR, 0644)
 	if err != nil {
 		return "", err
 	}
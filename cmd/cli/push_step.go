@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/spf13/cobra"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+)
+
+var pushStepCmd = &cobra.Command{
+	Use:   "step <url>",
+	Short: "Push step package",
+	Long: `The push step command uploads a single step package to an OCI repository.
+Step packages are referenced by steps with 'uses' and inlined into the pipeline, the manifest must be of kind StepPackage.`,
+	Example: `  # Push a step package to GHCR
+  rageta push step ghcr.io/org/steps/golangci:v1 \
+	--path="./golangci.yaml" \
+	--source="$(git config --get remote.origin.url)" \
+	--revision="$(git branch --show-current)@sha1:$(git rev-parse HEAD)"`,
+	Args: cobra.ExactArgs(1),
+	RunE: pushStepCmdRun,
+}
+
+func init() {
+	pushCmd.AddCommand(pushStepCmd)
+}
+
+func pushStepCmdRun(cmd *cobra.Command, args []string) error {
+	return pushArtifact(cmd, args, validateStepPackage)
+}
+
+func validateStepPackage(path string) error {
+	fstat, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	if fstat.IsDir() {
+		return errors.New("step packages must be pushed from a single manifest file")
+	}
+
+	manifest, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	scheme := kruntime.NewScheme()
+	_ = v1beta1.AddToScheme(scheme)
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	pkg := v1beta1.StepPackage{}
+	_, gvk, err := decoder.Decode(manifest, nil, &pkg)
+	if err != nil {
+		return fmt.Errorf("invalid step package: %w", err)
+	}
+
+	if gvk.Kind != "StepPackage" {
+		return fmt.Errorf("invalid step package: expected kind StepPackage but got %s", gvk.Kind)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/raffis/rageta/internal/run"
+	"github.com/spf13/cobra"
+)
+
+var removeCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a pipeline from the local database",
+	Example: `  # Remove a pipeline by its reference or alias
+  rageta remove ghcr.io/org/pipelines/build:v1
+  rageta remove build`,
+	Args: cobra.ExactArgs(1),
+	RunE: removeCmdRun,
+}
+
+func init() {
+	rootCmd.AddCommand(removeCmd)
+}
+
+func removeCmdRun(cmd *cobra.Command, args []string) error {
+	localDB, err := run.OpenDatabase(rootArgs.dbPath)
+	if err != nil {
+		return err
+	}
+
+	name := args[0]
+	if ref, ok := localDB.Lookup(name); ok {
+		name = ref
+	}
+
+	if err := localDB.Remove(name); err != nil {
+		return err
+	}
+
+	if err := run.PersistDatabase(rootArgs.dbPath, localDB); err != nil {
+		return fmt.Errorf("failed to persist database: %w", err)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "removed %s\n", name)
+	return nil
+}
@@ -24,8 +24,10 @@ type rootFlags struct {
 	timeout    time.Duration `env:"TIMEOUT"`
 	noColor    bool          `env:"NO_COLOR"`
 	dbPath     string        `env:"DB_PATH"`
+	cacheDir   string        `env:"CACHE_DIR"`
 	workDir    string        `env:"WORKDIR"`
 	logOptions *logsetup.Options
+	homeDir    string
 }
 
 var logger logr.Logger
@@ -69,10 +71,13 @@ func init() {
 		}
 	}
 
+	rootArgs.homeDir = homePath
 	dbPath := filepath.Join(homePath, "db.yaml")
+	cacheDir := filepath.Join(homePath, "cache")
 
 	rootCmd.PersistentFlags().StringVarP(&rootArgs.workDir, "workdir", "w", "", "Execute in the given directory.")
 	rootCmd.PersistentFlags().StringVarP(&rootArgs.dbPath, "db-path", "", dbPath, "Path to the local pipeline store.")
+	rootCmd.PersistentFlags().StringVarP(&rootArgs.cacheDir, "cache-dir", "", cacheDir, "Path to the cache directory for remote pipeline sources.")
 	rootCmd.PersistentFlags().DurationVarP(&rootArgs.timeout, "timeout", "", 0, "")
 	rootCmd.PersistentFlags().BoolVarP(&rootArgs.noColor, "no-color", "", false, "Disable all color output to the terminal.")
 	rootArgs.logOptions.BindFlags(rootCmd.PersistentFlags())
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/raffis/rageta/internal/run"
 	"github.com/spf13/cobra"
@@ -74,7 +75,17 @@ func init() {
 func runRun(cmd *cobra.Command, args []string) error {
 	runOpts.LoggingOptions.ZapConfig = zapConfig
 	runOpts.ProviderOptions.DBPath = rootArgs.dbPath
+	runOpts.ProviderOptions.CacheDir = rootArgs.cacheDir
+	runOpts.ProviderOptions.Inputs = runOpts.InputsOptions.Args
+	runOpts.ChangesOptions.CacheDir = rootArgs.cacheDir
+	runOpts.LocksOptions.Dir = filepath.Join(rootArgs.homeDir, "locks")
 	runOpts.LifecycleOptions.Timeout = rootArgs.timeout
+
+	// The plan is written to stdout, step output would only interfere with it
+	if runOpts.DryRunOptions.Enabled() && !cmd.Flags().Changed("output") {
+		runOpts.OutputOptions.Output = run.RenderOutputDiscard.String()
+	}
+
 	_, err := runOpts.Build().
 		Run(cmd.Context(), args, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.OutOrStderr())
 
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/raffis/rageta/internal/run"
+	"github.com/raffis/rageta/internal/runtime"
+	"github.com/raffis/rageta/pkg/apis/package/v1beta1"
+	"github.com/spf13/cobra"
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update [name]",
+	Short: "Update installed pipelines",
+	Long: `The update command resolves installed pipelines again from their remote source.
+Pipelines which were only tagged locally are skipped.`,
+	Example: `  # Update all installed pipelines
+  rageta update
+
+  # Update a single pipeline by its reference or alias
+  rageta update build`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: updateCmdRun,
+}
+
+type updateFlags struct {
+	providerOptions run.ProviderOptions
+}
+
+var updateArgs = newUpdateFlags()
+
+func newUpdateFlags() updateFlags {
+	return updateFlags{
+		providerOptions: run.NewProviderOptions(),
+	}
+}
+
+func init() {
+	updateArgs.providerOptions.BindFlags(updateCmd.Flags())
+	rootCmd.AddCommand(updateCmd)
+}
+
+func updateCmdRun(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if rootArgs.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rootArgs.timeout)
+		defer cancel()
+	}
+
+	localDB, err := run.OpenDatabase(rootArgs.dbPath)
+	if err != nil {
+		return err
+	}
+
+	var apps []v1beta1.App
+	if len(args) > 0 {
+		name := args[0]
+		if ref, ok := localDB.Lookup(name); ok {
+			name = ref
+		}
+
+		app, err := localDB.Get(name)
+		if err != nil {
+			return err
+		}
+
+		apps = append(apps, app)
+	} else {
+		apps = localDB.List()
+	}
+
+	opts := updateArgs.providerOptions
+	opts.DBPath = rootArgs.dbPath
+	opts.CacheDir = rootArgs.cacheDir
+	store, persistDB := run.CreateProvider(logger, runtime.PullImagePolicyAlways, opts)
+
+	var errs []error
+	for _, app := range apps {
+		if app.Digest == "" && app.Revision == "" {
+			logger.V(1).Info("skip locally tagged pipeline", "pipeline", app.Name)
+			continue
+		}
+
+		if _, err := store.Resolve(ctx, app.Name); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update %s: %w", app.Name, err))
+		}
+	}
+
+	if err := persistDB(); err != nil {
+		return fmt.Errorf("failed to persist database: %w", err)
+	}
+
+	updatedDB, err := run.OpenDatabase(rootArgs.dbPath)
+	if err != nil {
+		return err
+	}
+
+	for _, app := range apps {
+		updated, err := updatedDB.Get(app.Name)
+		if err != nil || app.Digest == "" && app.Revision == "" {
+			continue
+		}
+
+		if appVersion(updated) == appVersion(app) {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is up to date\n", app.Name)
+			continue
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "updated %s: %s -> %s\n", app.Name, appVersion(app), appVersion(updated))
+	}
+
+	return errors.Join(errs...)
+}
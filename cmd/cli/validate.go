@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/raffis/rageta/internal/pipeline"
+	"github.com/raffis/rageta/internal/provider"
+	"github.com/raffis/rageta/internal/run"
+	"github.com/raffis/rageta/internal/runtime"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/spf13/cobra"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"sigs.k8s.io/yaml"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [ref]",
+	Short: "Validate a pipeline",
+	Long: `The validate command loads a pipeline through the same providers as run and reports all problems found by a static analysis:
+unknown step references, dependency cycles, invalid step types, invalid cel expressions, substitutions referencing unknown inputs or steps,
+unused inputs and run steps without an image.
+The command exits with a non zero exit code if any error was found, warnings are only reported.`,
+	Example: `  # Validate rageta.yaml in the current directory
+  rageta validate
+
+  # Validate a remote pipeline and print the result as json
+  rageta validate ghcr.io/org/pipeline:v1 -o json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: validateCmdRun,
+}
+
+type validateFlags struct {
+	output          string
+	providerOptions run.ProviderOptions
+}
+
+var validateArgs = newValidateFlags()
+
+func newValidateFlags() validateFlags {
+	return validateFlags{
+		providerOptions: run.NewProviderOptions(),
+	}
+}
+
+func init() {
+	validateArgs.providerOptions.BindFlags(validateCmd.Flags())
+	validateCmd.Flags().StringVarP(&validateArgs.output, "output", "o", "", "the format in which the problems should be printed, can be 'json' or 'yaml'")
+	rootCmd.AddCommand(validateCmd)
+}
+
+type validateResult struct {
+	Ref      string             `json:"ref"`
+	Valid    bool               `json:"valid"`
+	Problems []pipeline.Problem `json:"problems"`
+}
+
+func validateCmdRun(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+	if rootArgs.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rootArgs.timeout)
+		defer cancel()
+	}
+
+	// An empty ref resolves rageta.yaml or rageta.star from the current directory
+	var ref string
+	if len(args) > 0 {
+		ref = args[0]
+	}
+
+	opts := validateArgs.providerOptions
+	opts.DBPath = rootArgs.dbPath
+	opts.CacheDir = rootArgs.cacheDir
+
+	store, persistDB := run.CreateProvider(logger, runtime.PullImagePolicyMissing, opts)
+	defer func() {
+		if err := persistDB(); err != nil {
+			logger.V(1).Error(err, "failed to persist database")
+		}
+	}()
+
+	manifest, err := store.Fetch(ctx, ref)
+	if err != nil {
+		return err
+	}
+
+	scheme := kruntime.NewScheme()
+	_ = v1beta1.AddToScheme(scheme)
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	spec := v1beta1.Pipeline{}
+	if _, _, err := decoder.Decode(manifest, nil, &spec); err != nil {
+		return fmt.Errorf("failed to decode pipeline: %w", err)
+	}
+
+	// Overlays and step packages are validated once merged into the pipeline, the positions of the manifest do not apply anymore
+	usesPackages := slices.ContainsFunc(spec.Steps, func(step v1beta1.Step) bool {
+		return step.Uses != ""
+	})
+
+	if spec.Extends != "" || len(spec.Patches) > 0 || usesPackages {
+		spec, err = store.Resolve(ctx, ref)
+		if err != nil {
+			return err
+		}
+
+		manifest = nil
+	}
+
+	celEnv, err := run.NewCELEnv(nil)
+	if err != nil {
+		return err
+	}
+
+	name := ref
+	if name == "" {
+		name = provider.RagetaFile
+	}
+
+	result := validateResult{
+		Ref:      name,
+		Valid:    true,
+		Problems: pipeline.Validate(spec, manifest, celEnv),
+	}
+
+	errors := 0
+	for _, problem := range result.Problems {
+		if problem.Severity == pipeline.SeverityError {
+			result.Valid = false
+			errors++
+		}
+	}
+
+	switch validateArgs.output {
+	case "json":
+		marshalled, err := json.MarshalIndent(&result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("validation result JSON conversion failed: %w", err)
+		}
+		marshalled = append(marshalled, "\n"...)
+		cmd.Print(string(marshalled))
+	case "yaml":
+		marshalled, err := yaml.Marshal(&result)
+		if err != nil {
+			return fmt.Errorf("validation result YAML conversion failed: %w", err)
+		}
+		cmd.Print(string(marshalled))
+	default:
+		for _, problem := range result.Problems {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s:%s\n", name, problem)
+		}
+
+		if len(result.Problems) == 0 {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s is valid\n", name)
+		}
+	}
+
+	if !result.Valid {
+		// The problems are already reported, usage help is not of any use here
+		cmd.SilenceUsage = true
+		return fmt.Errorf("pipeline %s has %d error(s)", name, errors)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package approval
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+// currentUser returns the name of the user running the pipeline which is recorded as approver for local decisions.
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+
+	return os.Getenv("USER")
+}
+
+// parseDecision parses an answer given by an approver.
+func parseDecision(answer string) (v1beta1.ApprovalDecision, bool) {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "approve", "approved":
+		return v1beta1.ApprovalDecisionApprove, true
+	case "n", "no", "reject", "rejected":
+		return v1beta1.ApprovalDecisionReject, true
+	}
+
+	return "", false
+}
+
+type always struct {
+	decision v1beta1.ApprovalDecision
+	approver string
+}
+
+// Always decides all approvals with the same decision without asking anyone.
+func Always(decision v1beta1.ApprovalDecision, approver string) processor.Approver {
+	return &always{
+		decision: decision,
+		approver: approver,
+	}
+}
+
+func (a *always) Approve(ctx context.Context, request processor.ApprovalRequest) (processor.ApprovalResult, error) {
+	return processor.ApprovalResult{
+		Decision: a.decision,
+		Approver: a.approver,
+	}, nil
+}
+
+type requireDeadline struct {
+	approver processor.Approver
+}
+
+// RequireDeadline fails approvals which would wait without a deadline.
+// It is used if nobody is known to decide approvals, e.g. without a terminal, so the pipeline does not block forever.
+func RequireDeadline(approver processor.Approver) processor.Approver {
+	return &requireDeadline{
+		approver: approver,
+	}
+}
+
+func (a *requireDeadline) Approve(ctx context.Context, request processor.ApprovalRequest) (processor.ApprovalResult, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		return processor.ApprovalResult{}, fmt.Errorf("step %s requires an approval timeout or an explicit approver given by --approval if stdin is not a terminal", request.StepName)
+	}
+
+	return a.approver.Approve(ctx, request)
+}
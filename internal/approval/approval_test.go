@@ -0,0 +1,141 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrompt(t *testing.T) {
+	out := &strings.Builder{}
+	approver := Prompt(strings.NewReader("maybe\nyes\n"), out)
+
+	result, err := approver.Approve(context.Background(), processor.ApprovalRequest{
+		StepName: "deploy",
+		Message:  "Apply the plan?",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, v1beta1.ApprovalDecisionApprove, result.Decision)
+	assert.Equal(t, "Apply the plan?\nApprove step deploy? [y/n]: Approve step deploy? [y/n]: ", out.String())
+
+	_, err = approver.Approve(context.Background(), processor.ApprovalRequest{StepName: "deploy"})
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestFile(t *testing.T) {
+	dir := t.TempDir()
+	approver := File(dir)
+
+	done := make(chan struct{})
+	var result processor.ApprovalResult
+	var err error
+
+	go func() {
+		defer close(done)
+		result, err = approver.Approve(context.Background(), processor.ApprovalRequest{
+			StepName: "deploy",
+			Message:  "Apply the plan?",
+		})
+	}()
+
+	require.Eventually(t, func() bool {
+		message, err := os.ReadFile(filepath.Join(dir, "deploy.pending"))
+		return err == nil && string(message) == "Apply the plan?"
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "deploy.reject"), []byte("alice\n"), 0600))
+	<-done
+
+	require.NoError(t, err)
+	assert.Equal(t, processor.ApprovalResult{Decision: v1beta1.ApprovalDecisionReject, Approver: "alice"}, result)
+	assert.NoFileExists(t, filepath.Join(dir, "deploy.pending"))
+	assert.NoFileExists(t, filepath.Join(dir, "deploy.reject"))
+}
+
+func TestFile_Cancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := File(t.TempDir()).Approve(ctx, processor.ApprovalRequest{StepName: "deploy"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRequireDeadline(t *testing.T) {
+	approver := RequireDeadline(Always(v1beta1.ApprovalDecisionApprove, "alice"))
+
+	_, err := approver.Approve(context.Background(), processor.ApprovalRequest{StepName: "deploy"})
+	assert.ErrorContains(t, err, "step deploy requires an approval timeout")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	result, err := approver.Approve(ctx, processor.ApprovalRequest{StepName: "deploy"})
+	require.NoError(t, err)
+	assert.Equal(t, processor.ApprovalResult{Decision: v1beta1.ApprovalDecisionApprove, Approver: "alice"}, result)
+}
+
+func TestHTTP(t *testing.T) {
+	server, err := HTTP("127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() {
+		_ = server.Close()
+	}()
+
+	endpoint := "http://" + server.Addr() + "/approvals"
+
+	done := make(chan struct{})
+	var result processor.ApprovalResult
+	var approveErr error
+
+	go func() {
+		defer close(done)
+		result, approveErr = server.Approve(context.Background(), processor.ApprovalRequest{
+			StepName: "deploy",
+			Message:  "Apply the plan?",
+		})
+	}()
+
+	require.Eventually(t, func() bool {
+		res, err := http.Get(endpoint)
+		if err != nil {
+			return false
+		}
+
+		defer func() {
+			_ = res.Body.Close()
+		}()
+
+		var pending []approvalResponse
+		return json.NewDecoder(res.Body).Decode(&pending) == nil && len(pending) == 1 && pending[0].Message == "Apply the plan?"
+	}, time.Second, 10*time.Millisecond)
+
+	res, err := http.Post(endpoint+"/unknown/approve", "", nil)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+
+	req, err := http.NewRequest(http.MethodPost, endpoint+"/deploy/approve", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Approver", "alice")
+
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	<-done
+	require.NoError(t, approveErr)
+	assert.Equal(t, processor.ApprovalResult{Decision: v1beta1.ApprovalDecisionApprove, Approver: "alice"}, result)
+}
@@ -0,0 +1,77 @@
+package approval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+const filePollInterval = 500 * time.Millisecond
+
+type file struct {
+	dir string
+}
+
+// File waits for a decision file to be created within the given directory.
+// A pending approval is announced by the file <step>.pending which contains the approval message.
+// The step is approved by creating <step>.approve or rejected by creating <step>.reject, the content of it is recorded as the approver.
+func File(dir string) processor.Approver {
+	return &file{
+		dir: dir,
+	}
+}
+
+func (f *file) Approve(ctx context.Context, request processor.ApprovalRequest) (processor.ApprovalResult, error) {
+	if err := os.MkdirAll(f.dir, 0700); err != nil {
+		return processor.ApprovalResult{}, fmt.Errorf("failed to create approval dir: %w", err)
+	}
+
+	base := filepath.Join(f.dir, request.StepName)
+	decisions := map[v1beta1.ApprovalDecision]string{
+		v1beta1.ApprovalDecisionApprove: base + ".approve",
+		v1beta1.ApprovalDecisionReject:  base + ".reject",
+	}
+
+	if err := os.WriteFile(base+".pending", []byte(request.Message), 0600); err != nil {
+		return processor.ApprovalResult{}, fmt.Errorf("failed to create pending approval: %w", err)
+	}
+
+	defer func() {
+		_ = os.Remove(base + ".pending")
+	}()
+
+	ticker := time.NewTicker(filePollInterval)
+	defer ticker.Stop()
+
+	for {
+		for decision, path := range decisions {
+			approver, err := os.ReadFile(path)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			if err != nil {
+				return processor.ApprovalResult{}, fmt.Errorf("failed to read decision: %w", err)
+			}
+
+			_ = os.Remove(path)
+			return processor.ApprovalResult{
+				Decision: decision,
+				Approver: strings.TrimSpace(string(approver)),
+			}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return processor.ApprovalResult{}, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+type pendingApproval struct {
+	request processor.ApprovalRequest
+	reply   chan processor.ApprovalResult
+}
+
+type approvalResponse struct {
+	Step     string                   `json:"step"`
+	Message  string                   `json:"message,omitempty"`
+	Decision v1beta1.ApprovalDecision `json:"decision,omitempty"`
+	Approver string                   `json:"approver,omitempty"`
+}
+
+type httpServer struct {
+	listener net.Listener
+	server   *http.Server
+	pending  map[string]pendingApproval
+	mu       sync.Mutex
+}
+
+// HTTP serves pending approvals on a local http endpoint.
+// GET /approvals lists the pending approvals while POST /approvals/{step}/approve and POST /approvals/{step}/reject decide them.
+// The approver is taken from the X-Approver header or the approver query parameter.
+func HTTP(addr string) (*httpServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &httpServer{
+		listener: listener,
+		pending:  make(map[string]pendingApproval),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /approvals", s.list)
+	mux.HandleFunc("POST /approvals/{step}/approve", s.decide(v1beta1.ApprovalDecisionApprove))
+	mux.HandleFunc("POST /approvals/{step}/reject", s.decide(v1beta1.ApprovalDecisionReject))
+	s.server = &http.Server{Handler: mux}
+
+	go func() {
+		_ = s.server.Serve(listener)
+	}()
+
+	return s, nil
+}
+
+// Addr returns the address the server listens on.
+func (s *httpServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *httpServer) Close() error {
+	return s.server.Close()
+}
+
+func (s *httpServer) Approve(ctx context.Context, request processor.ApprovalRequest) (processor.ApprovalResult, error) {
+	reply := make(chan processor.ApprovalResult, 1)
+
+	s.mu.Lock()
+	if _, ok := s.pending[request.StepName]; ok {
+		s.mu.Unlock()
+		return processor.ApprovalResult{}, errors.New("approval is already pending")
+	}
+
+	s.pending[request.StepName] = pendingApproval{
+		request: request,
+		reply:   reply,
+	}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, request.StepName)
+		s.mu.Unlock()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return processor.ApprovalResult{}, ctx.Err()
+	case result := <-reply:
+		return result, nil
+	}
+}
+
+func (s *httpServer) list(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	approvals := make([]approvalResponse, 0, len(s.pending))
+	for _, pending := range s.pending {
+		approvals = append(approvals, approvalResponse{
+			Step:    pending.request.StepName,
+			Message: pending.request.Message,
+		})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(approvals, func(i, j int) bool {
+		return approvals[i].Step < approvals[j].Step
+	})
+
+	writeJSON(w, http.StatusOK, approvals)
+}
+
+func (s *httpServer) decide(decision v1beta1.ApprovalDecision) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		step := r.PathValue("step")
+		approver := r.Header.Get("X-Approver")
+		if approver == "" {
+			approver = r.URL.Query().Get("approver")
+		}
+
+		s.mu.Lock()
+		pending, ok := s.pending[step]
+		delete(s.pending, step)
+		s.mu.Unlock()
+
+		if !ok {
+			http.Error(w, "no pending approval for step "+step, http.StatusNotFound)
+			return
+		}
+
+		pending.reply <- processor.ApprovalResult{
+			Decision: decision,
+			Approver: approver,
+		}
+
+		writeJSON(w, http.StatusOK, approvalResponse{
+			Step:     step,
+			Decision: decision,
+			Approver: approver,
+		})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
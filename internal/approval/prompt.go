@@ -0,0 +1,63 @@
+package approval
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/internal/xio"
+)
+
+type prompt struct {
+	in  io.Reader
+	out io.Writer
+	mu  sync.Mutex
+}
+
+// Prompt asks for decisions on the terminal, concurrent approvals are asked one after another.
+// Input is only read while an approval is requested.
+func Prompt(in io.Reader, out io.Writer) processor.Approver {
+	return &prompt{
+		in:  in,
+		out: out,
+	}
+}
+
+func (p *prompt) Approve(ctx context.Context, request processor.ApprovalRequest) (processor.ApprovalResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	lines, cancel, err := xio.ReadLines(ctx, p.in)
+	if err != nil {
+		return processor.ApprovalResult{}, err
+	}
+
+	defer cancel()
+
+	if request.Message != "" {
+		fmt.Fprintf(p.out, "%s\n", request.Message)
+	}
+
+	for {
+		fmt.Fprintf(p.out, "Approve step %s? [y/n]: ", request.StepName)
+
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(p.out)
+			return processor.ApprovalResult{}, ctx.Err()
+		case line, ok := <-lines:
+			if !ok {
+				return processor.ApprovalResult{}, io.ErrUnexpectedEOF
+			}
+
+			if decision, ok := parseDecision(line); ok {
+				return processor.ApprovalResult{
+					Decision: decision,
+					Approver: currentUser(),
+				}, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package approval
+
+import (
+	"context"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/internal/tui"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+type ui struct {
+	program *tea.Program
+}
+
+// UI asks for decisions using a modal within the terminal ui.
+func UI(program *tea.Program) processor.Approver {
+	return &ui{
+		program: program,
+	}
+}
+
+func (u *ui) Approve(ctx context.Context, request processor.ApprovalRequest) (processor.ApprovalResult, error) {
+	reply := make(chan v1beta1.ApprovalDecision, 1)
+	u.program.Send(tui.ApprovalMsg{
+		Request: request,
+		Reply:   reply,
+	})
+
+	select {
+	case <-ctx.Done():
+		u.program.Send(tui.ApprovalDismissMsg{StepName: request.StepName})
+		return processor.ApprovalResult{}, ctx.Err()
+	case decision := <-reply:
+		return processor.ApprovalResult{
+			Decision: decision,
+			Approver: currentUser(),
+		}, nil
+	}
+}
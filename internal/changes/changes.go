@@ -0,0 +1,43 @@
+package changes
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// Detector reports whether files matching a pattern changed.
+// Patterns use the gitignore syntax and are relative to the working directory, a pattern without a slash matches at any depth.
+type Detector interface {
+	Changed(pattern string) (bool, error)
+}
+
+type always struct{}
+
+// Always reports every pattern as changed.
+func Always() Detector {
+	return always{}
+}
+
+func (always) Changed(pattern string) (bool, error) {
+	return true, nil
+}
+
+// Match reports whether the slash separated file path matches the pattern.
+func Match(pattern, file string) bool {
+	p := gitignore.ParsePattern(pattern, nil)
+	return p.Match(strings.Split(filepath.ToSlash(file), "/"), false) == gitignore.Exclude
+}
+
+// AnyChanged reports whether files matching any of the patterns changed.
+func AnyChanged(detector Detector, patterns []string) (bool, error) {
+	for _, pattern := range patterns {
+		changed, err := detector.Changed(pattern)
+		if err != nil || changed {
+			return changed, err
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,257 @@
+package changes
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		match   bool
+	}{
+		{pattern: "*.go", file: "main.go", match: true},
+		{pattern: "*.go", file: "internal/run/run.go", match: true},
+		{pattern: "/*.go", file: "internal/run/run.go", match: false},
+		{pattern: "internal/**", file: "internal/run/run.go", match: true},
+		{pattern: "internal", file: "internal/run/run.go", match: true},
+		{pattern: "docs/**/*.md", file: "docs/a/b/readme.md", match: true},
+		{pattern: "docs/**/*.md", file: "internal/readme.md", match: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.file, func(t *testing.T) {
+			assert.Equal(t, tt.match, Match(tt.pattern, tt.file))
+		})
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestHashes(t *testing.T) {
+	dir := t.TempDir()
+	stateFile := filepath.Join(t.TempDir(), "state", "hashes.json")
+	writeFile(t, dir, "src/main.go", "package main")
+	writeFile(t, dir, "docs/readme.md", "# readme")
+
+	detector := Hashes(dir, stateFile)
+	changed, err := detector.Changed("*.go")
+	require.NoError(t, err)
+	assert.True(t, changed, "without recorded hashes every file is changed")
+	_, err = detector.Changed("*.md")
+	require.NoError(t, err)
+	require.NoError(t, detector.Save())
+
+	detector = Hashes(dir, stateFile)
+	changed, err = detector.Changed("*.go")
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	writeFile(t, dir, "src/main.go", "package main\n")
+	require.NoError(t, os.Remove(filepath.Join(dir, "docs/readme.md")))
+
+	detector = Hashes(dir, stateFile)
+	changed, err = detector.Changed("src/**")
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	changed, err = detector.Changed("*.md")
+	require.NoError(t, err)
+	assert.True(t, changed, "removed files are changed")
+
+	changed, err = detector.Changed("*.txt")
+	require.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func commit(t *testing.T, repo *git.Repository, msg string) plumbing.Hash {
+	t.Helper()
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, worktree.AddGlob("."))
+
+	hash, err := worktree.Commit(msg, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+	})
+	require.NoError(t, err)
+	return hash
+}
+
+func TestGit(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+
+	writeFile(t, dir, "app/main.go", "package main")
+	writeFile(t, dir, "app/docs/readme.md", "# readme")
+	writeFile(t, dir, "other/main.go", "package main")
+	base := commit(t, repo, "initial")
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference("refs/heads/main", base)))
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: "refs/heads/feature", Create: true}))
+
+	writeFile(t, dir, "app/main.go", "package main\n")
+	commit(t, repo, "feature")
+	writeFile(t, dir, "app/untracked.txt", "new")
+	writeFile(t, dir, "other/main.go", "package other")
+
+	t.Run("repository root", func(t *testing.T) {
+		detector := Git(dir, "")
+		for pattern, expected := range map[string]bool{
+			"app/main.go":   true,
+			"*.txt":         true,
+			"other/*.go":    true,
+			"*.md":          false,
+			"/main.go":      false,
+			"app/docs/**":   false,
+			"app/*.go":      true,
+			"does-not-*":    false,
+			"app/untracked": false,
+		} {
+			changed, err := detector.Changed(pattern)
+			require.NoError(t, err)
+			assert.Equal(t, expected, changed, pattern)
+		}
+	})
+
+	t.Run("subdirectory", func(t *testing.T) {
+		detector := Git(filepath.Join(dir, "app"), "main")
+		changed, err := detector.Changed("/main.go")
+		require.NoError(t, err)
+		assert.True(t, changed)
+
+		changed, err = detector.Changed("other/**")
+		require.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("unknown base", func(t *testing.T) {
+		_, err := Git(dir, "does-not-exist").Changed("*.go")
+		assert.ErrorContains(t, err, `failed to resolve base ref "does-not-exist"`)
+	})
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "src/main.go", "package main")
+	writeFile(t, dir, "src/util.go", "package main")
+	writeFile(t, dir, "docs/readme.md", "# readme")
+
+	watcher, err := NewWatcher(dir, []string{"*.go"}, 10*time.Millisecond, 50*time.Millisecond)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		writeFile(t, dir, "docs/readme.md", "# changed readme")
+		writeFile(t, dir, "src/main.go", "package main\n\nfunc main() {}")
+		time.Sleep(20 * time.Millisecond)
+		_ = os.Remove(filepath.Join(dir, "src", "util.go"))
+		writeFile(t, dir, "src/new.go", "package main")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	files, err := watcher.Wait(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"src/main.go", "src/new.go", "src/util.go"}, files)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = watcher.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "no changes are reported twice")
+}
+
+func TestWatcherReset(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "src/main.go", "package main")
+
+	watcher, err := NewWatcher(dir, nil, 10*time.Millisecond, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	writeFile(t, dir, "bin/output.go", "package bin")
+	require.NoError(t, watcher.Reset())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = watcher.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "changes before the reset are not reported")
+}
+
+// shallow removes the given commit from the repository as if it was cloned with a limited depth.
+func shallow(t *testing.T, dir string, boundary []plumbing.Hash, missing plumbing.Hash) {
+	t.Helper()
+	require.NoError(t, os.Remove(filepath.Join(dir, ".git", "objects", missing.String()[:2], missing.String()[2:])))
+
+	var content string
+	for _, hash := range boundary {
+		content += hash.String() + "\n"
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "shallow"), []byte(content), 0644))
+}
+
+func TestGitShallow(t *testing.T) {
+	t.Run("parent of HEAD is missing", func(t *testing.T) {
+		dir := t.TempDir()
+		repo, err := git.PlainInit(dir, false)
+		require.NoError(t, err)
+
+		writeFile(t, dir, "app/main.go", "package main")
+		initial := commit(t, repo, "initial")
+		writeFile(t, dir, "app/main.go", "package main\n")
+		head := commit(t, repo, "second")
+		require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference("refs/heads/main", head)))
+		shallow(t, dir, []plumbing.Hash{head}, initial)
+
+		changed, err := Git(dir, "").Changed("*.md")
+		require.NoError(t, err)
+		assert.True(t, changed, "every pattern is changed without a base")
+	})
+
+	t.Run("merge base is missing", func(t *testing.T) {
+		dir := t.TempDir()
+		repo, err := git.PlainInit(dir, false)
+		require.NoError(t, err)
+
+		writeFile(t, dir, "app/main.go", "package main")
+		initial := commit(t, repo, "initial")
+		writeFile(t, dir, "app/main.go", "package main\n")
+		base := commit(t, repo, "main")
+		require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference("refs/remotes/origin/main", base)))
+
+		worktree, err := repo.Worktree()
+		require.NoError(t, err)
+		require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Hash: initial, Branch: "refs/heads/feature", Create: true}))
+		writeFile(t, dir, "app/feature.go", "package main")
+		head := commit(t, repo, "feature")
+		shallow(t, dir, []plumbing.Hash{base, head}, initial)
+
+		changed, err := Git(dir, "").Changed("*.md")
+		require.NoError(t, err)
+		assert.True(t, changed, "every pattern is changed without a base")
+
+		changed, err = Git(dir, "origin/main").Changed("*.md")
+		require.NoError(t, err)
+		assert.True(t, changed, "every pattern is changed without a base")
+	})
+}
@@ -0,0 +1,251 @@
+package changes
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Default branches the base is looked up from if no base ref is given.
+var defaultBaseRefs = []plumbing.ReferenceName{
+	"refs/remotes/origin/HEAD",
+	"refs/remotes/origin/main",
+	"refs/remotes/origin/master",
+	"refs/heads/main",
+	"refs/heads/master",
+}
+
+type gitDetector struct {
+	dir   string
+	base  string
+	once  sync.Once
+	files []string
+	all   bool
+	err   error
+}
+
+// Git detects changes of the working tree against the merge base of HEAD and the base ref.
+// Without a base ref the merge base with the default branch is used, on the default branch itself HEAD is compared against its parent.
+// Uncommitted and untracked files are changed as well. If no base can be found, e.g. in a shallow clone, every pattern is reported as changed.
+func Git(dir, base string) Detector {
+	return &gitDetector{
+		dir:  dir,
+		base: base,
+	}
+}
+
+func (d *gitDetector) Changed(pattern string) (bool, error) {
+	d.once.Do(func() {
+		d.files, d.all, d.err = d.changedFiles()
+	})
+
+	if d.err != nil || d.all {
+		return d.all, d.err
+	}
+
+	for _, file := range d.files {
+		if Match(pattern, file) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// changedFiles returns the changed files relative to the working directory.
+func (d *gitDetector) changedFiles() ([]string, bool, error) {
+	repo, err := git.PlainOpenWithOptions(d.dir, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open git repository: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open git worktree: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to resolve HEAD: %w", err)
+	}
+
+	baseCommit, err := d.resolveBase(repo, headCommit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if baseCommit == nil {
+		return nil, true, nil
+	}
+
+	var files []string
+	if baseCommit.Hash != headCommit.Hash {
+		diff, err := diffCommits(baseCommit, headCommit)
+		if err != nil {
+			return nil, false, err
+		}
+
+		files = append(files, diff...)
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get worktree status: %w", err)
+	}
+
+	for file, s := range status {
+		if s.Worktree != git.Unmodified || s.Staging != git.Unmodified {
+			files = append(files, file)
+		}
+	}
+
+	root := worktree.Filesystem.Root()
+	return relativeTo(root, d.dir, files)
+}
+
+func (d *gitDetector) resolveBase(repo *git.Repository, head *object.Commit) (*object.Commit, error) {
+	if d.base != "" {
+		hash, err := repo.ResolveRevision(plumbing.Revision(d.base))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve base ref %q: %w", d.base, err)
+		}
+
+		base, err := repo.CommitObject(*hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve base ref %q: %w", d.base, err)
+		}
+
+		return mergeBase(head, base)
+	}
+
+	for _, name := range defaultBaseRefs {
+		ref, err := repo.Reference(name, true)
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", name, err)
+		}
+
+		base, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", name, err)
+		}
+
+		mb, err := mergeBase(head, base)
+		if err != nil || mb == nil || mb.Hash != head.Hash {
+			return mb, err
+		}
+
+		// HEAD is on the default branch, the changes of the last commit are compared
+		if head.NumParents() == 0 {
+			return nil, nil
+		}
+
+		parent, err := head.Parent(0)
+		// The parent is missing in a shallow clone
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil, nil
+		}
+
+		return parent, err
+	}
+
+	return nil, nil
+}
+
+func mergeBase(head, base *object.Commit) (*object.Commit, error) {
+	bases, err := head.MergeBase(base)
+	// The history of a shallow clone might not reach the merge base
+	if errors.Is(err, plumbing.ErrObjectNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge base: %w", err)
+	}
+
+	if len(bases) == 0 {
+		return nil, nil
+	}
+
+	return bases[0], nil
+}
+
+func diffCommits(from, to *object.Commit) ([]string, error) {
+	fromTree, err := from.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	toTree, err := to.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s..%s: %w", from.Hash, to.Hash, err)
+	}
+
+	var files []string
+	for _, change := range changes {
+		if change.From.Name != "" {
+			files = append(files, change.From.Name)
+		}
+
+		if change.To.Name != "" && change.To.Name != change.From.Name {
+			files = append(files, change.To.Name)
+		}
+	}
+
+	return files, nil
+}
+
+// relativeTo converts paths relative to the repository root to paths relative to dir, files outside of dir are dropped.
+func relativeTo(root, dir string, files []string) ([]string, bool, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The worktree root is not symlink resolved while the working directory might be
+	if resolved, err := filepath.EvalSymlinks(absDir); err == nil {
+		absDir = resolved
+	}
+
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	prefix, err := filepath.Rel(root, absDir)
+	if err != nil {
+		return nil, false, err
+	}
+
+	prefix = filepath.ToSlash(prefix)
+	if prefix == "." {
+		return files, false, nil
+	}
+
+	var result []string
+	for _, file := range files {
+		if rel, ok := strings.CutPrefix(file, prefix+"/"); ok {
+			result = append(result, rel)
+		}
+	}
+
+	return result, false, nil
+}
@@ -0,0 +1,192 @@
+package changes
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type hashes struct {
+	dir       string
+	stateFile string
+	mu        sync.Mutex
+	loaded    bool
+	recorded  map[string]string
+	current   map[string]string
+	files     []string
+}
+
+// Hashes detects changes by comparing file hashes against the hashes recorded by the last successful run.
+// Patterns which were never recorded before are reported as changed, the hashes are recorded by calling Save.
+func Hashes(dir, stateFile string) *hashes {
+	return &hashes{
+		dir:       dir,
+		stateFile: stateFile,
+		current:   make(map[string]string),
+	}
+}
+
+func (h *hashes) Changed(pattern string) (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.load(); err != nil {
+		return false, err
+	}
+
+	changed := h.recorded == nil
+	matched := make(map[string]bool)
+
+	for _, file := range h.files {
+		if !Match(pattern, file) {
+			continue
+		}
+
+		sum, err := h.hash(file)
+		if err != nil {
+			return false, err
+		}
+
+		matched[file] = true
+		if h.recorded[file] != sum {
+			changed = true
+		}
+	}
+
+	// Recorded files which do not exist anymore
+	for file := range h.recorded {
+		if !matched[file] && Match(pattern, file) {
+			changed = true
+		}
+	}
+
+	return changed, nil
+}
+
+// Save records the hashes of all files matched so far.
+func (h *hashes) Save() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.current) == 0 {
+		return nil
+	}
+
+	state := make(map[string]string, len(h.recorded)+len(h.current))
+	for file, sum := range h.recorded {
+		if _, err := os.Stat(filepath.Join(h.dir, file)); err == nil {
+			state[file] = sum
+		}
+	}
+
+	for file, sum := range h.current {
+		state[file] = sum
+	}
+
+	b, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.stateFile), 0700); err != nil {
+		return fmt.Errorf("failed to create state dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(h.stateFile), filepath.Base(h.stateFile))
+	if err != nil {
+		return fmt.Errorf("failed to write source hashes: %w", err)
+	}
+
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write source hashes: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write source hashes: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), h.stateFile)
+}
+
+// load reads the recorded hashes and lists the files of the working directory once.
+func (h *hashes) load() error {
+	if h.loaded {
+		return nil
+	}
+
+	b, err := os.ReadFile(h.stateFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to read source hashes: %w", err)
+	default:
+		if err := json.Unmarshal(b, &h.recorded); err != nil {
+			return fmt.Errorf("failed to decode source hashes: %w", err)
+		}
+	}
+
+	err = filepath.WalkDir(h.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(h.dir, path)
+		if err != nil {
+			return err
+		}
+
+		h.files = append(h.files, filepath.ToSlash(rel))
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to list files: %w", err)
+	}
+
+	h.loaded = true
+	return nil
+}
+
+func (h *hashes) hash(file string) (string, error) {
+	if sum, ok := h.current[file]; ok {
+		return sum, nil
+	}
+
+	f, err := os.Open(filepath.Join(h.dir, file))
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", file, err)
+	}
+
+	sum := hex.EncodeToString(hasher.Sum(nil))
+	h.current[file] = sum
+	return sum, nil
+}
@@ -0,0 +1,155 @@
+package changes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watcher polls the working directory for changed files.
+// Only files matching any of the patterns are watched, all files are watched without patterns.
+type Watcher struct {
+	dir      string
+	patterns []string
+	interval time.Duration
+	debounce time.Duration
+	files    map[string]fileState
+}
+
+// NewWatcher records the current state of the watched files, changes are reported relative to it.
+func NewWatcher(dir string, patterns []string, interval, debounce time.Duration) (*Watcher, error) {
+	w := &Watcher{
+		dir:      dir,
+		patterns: patterns,
+		interval: interval,
+		debounce: debounce,
+	}
+
+	if err := w.Reset(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// Reset records the current state of the watched files, changes made until now are not reported.
+// It is used to ignore files written by the steps themselves.
+func (w *Watcher) Reset() error {
+	files, err := w.scan()
+	if err != nil {
+		return err
+	}
+
+	w.files = files
+	return nil
+}
+
+// Wait blocks until files changed and no further change happened within the debounce duration.
+// It returns the slash separated paths of the added, modified and removed files.
+func (w *Watcher) Wait(ctx context.Context) ([]string, error) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	changed := make(map[string]struct{})
+	var lastChange time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		files, err := w.scan()
+		if err != nil {
+			return nil, err
+		}
+
+		for file, state := range files {
+			if previous, ok := w.files[file]; !ok || previous != state {
+				changed[file] = struct{}{}
+				lastChange = time.Now()
+			}
+		}
+
+		for file := range w.files {
+			if _, ok := files[file]; !ok {
+				changed[file] = struct{}{}
+				lastChange = time.Now()
+			}
+		}
+
+		w.files = files
+
+		if len(changed) > 0 && time.Since(lastChange) >= w.debounce {
+			return slices.Sorted(maps.Keys(changed)), nil
+		}
+	}
+}
+
+func (w *Watcher) scan() (map[string]fileState, error) {
+	files := make(map[string]fileState)
+
+	err := filepath.WalkDir(w.dir, func(path string, d fs.DirEntry, err error) error {
+		// Files might be removed while the directory is walked, e.g. temporary files
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(w.dir, path)
+		if err != nil {
+			return err
+		}
+
+		rel = filepath.ToSlash(rel)
+		if len(w.patterns) > 0 && !slices.ContainsFunc(w.patterns, func(pattern string) bool {
+			return Match(pattern, rel)
+		}) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		files[rel] = fileState{
+			modTime: info.ModTime(),
+			size:    info.Size(),
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	return files, nil
+}
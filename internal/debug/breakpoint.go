@@ -0,0 +1,170 @@
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/internal/xio"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+const breakpointHelp = `Commands:
+  c, continue         continue the pipeline
+  s, step             continue and pause before the next step
+  a, abort            abort the pipeline
+  p, print            print the context as json
+  input NAME=VALUE    set an input for the remaining steps
+  env NAME=VALUE      set an env variable for the remaining steps
+  h, help             print this help
+`
+
+type breakpoint struct {
+	terminal Terminal
+	stdin    io.Reader
+	stdout   io.Writer
+	mu       sync.Mutex
+	stepping bool
+	helped   bool
+}
+
+// Breakpoint pauses the pipeline at breakpoints and asks for commands on the terminal.
+// Concurrent breakpoints are handled one after another.
+func Breakpoint(terminal Terminal, stdin io.Reader, stdout io.Writer) processor.Breakpointer {
+	return &breakpoint{
+		terminal: terminal,
+		stdin:    stdin,
+		stdout:   stdout,
+	}
+}
+
+func (b *breakpoint) Stepping() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.stepping
+}
+
+func (b *breakpoint) Break(ctx context.Context, request processor.BreakpointRequest) (processor.BreakpointResult, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.terminal != nil {
+		if err := b.terminal.ReleaseTerminal(); err != nil {
+			return processor.BreakpointResult{}, fmt.Errorf("failed to release terminal: %w", err)
+		}
+
+		defer func() {
+			_ = b.terminal.RestoreTerminal()
+		}()
+	}
+
+	lines, cancel, err := xio.ReadLines(ctx, b.stdin)
+	if err != nil {
+		return processor.BreakpointResult{}, err
+	}
+
+	defer cancel()
+
+	result := processor.BreakpointResult{
+		Inputs: make(map[string]v1beta1.ParamValue),
+		Envs:   make(map[string]string),
+	}
+
+	fmt.Fprintf(b.stdout, "Paused %s step %s\n", request.Position, request.StepName)
+	if !b.helped {
+		fmt.Fprint(b.stdout, breakpointHelp)
+		b.helped = true
+	}
+
+	for {
+		fmt.Fprint(b.stdout, "> ")
+
+		var line string
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(b.stdout)
+			return result, ctx.Err()
+		case l, ok := <-lines:
+			if !ok {
+				return result, io.ErrUnexpectedEOF
+			}
+
+			line = l
+		}
+
+		command, args, _ := strings.Cut(strings.TrimSpace(line), " ")
+		switch command {
+		case "c", "continue":
+			b.stepping = false
+			result.Action = processor.BreakpointActionContinue
+			return result, nil
+		case "s", "step":
+			b.stepping = true
+			result.Action = processor.BreakpointActionStep
+			return result, nil
+		case "a", "abort":
+			result.Action = processor.BreakpointActionAbort
+			return result, nil
+		case "p", "print":
+			if err := b.print(request.Context); err != nil {
+				return result, err
+			}
+		case "input", "env":
+			name, value, ok := strings.Cut(strings.TrimSpace(args), "=")
+			if !ok || name == "" {
+				fmt.Fprintf(b.stdout, "Expected %s NAME=VALUE\n", command)
+				continue
+			}
+
+			if command == "input" {
+				result.Inputs[name] = *v1beta1.NewStructuredValues(value)
+			} else {
+				result.Envs[name] = value
+			}
+
+			setContext(request.Context, command, name, value)
+		case "h", "help":
+			fmt.Fprint(b.stdout, breakpointHelp)
+		case "":
+		default:
+			fmt.Fprintf(b.stdout, "Unknown command %q\n%s", command, breakpointHelp)
+		}
+	}
+}
+
+func (b *breakpoint) print(vars *v1beta1.Context) error {
+	out, err := json.MarshalIndent(vars, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode context: %w", err)
+	}
+
+	_, err = fmt.Fprintf(b.stdout, "%s\n", out)
+	return err
+}
+
+// setContext applies a changed input or env to the printed context.
+func setContext(vars *v1beta1.Context, kind, name, value string) {
+	if vars == nil {
+		return
+	}
+
+	switch kind {
+	case "input":
+		if vars.Inputs == nil {
+			vars.Inputs = make(map[string]v1beta1.ParamValue)
+		}
+
+		vars.Inputs[name] = *v1beta1.NewStructuredValues(value)
+	case "env":
+		if vars.Envs == nil {
+			vars.Envs = make(map[string]string)
+		}
+
+		vars.Envs[name] = value
+	}
+}
@@ -0,0 +1,101 @@
+package debug
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreakpoint(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectResult   processor.BreakpointResult
+		expectStepping bool
+		expectOutput   []string
+		expectError    error
+	}{
+		{
+			name:  "continue",
+			input: "c\n",
+			expectResult: processor.BreakpointResult{
+				Action: processor.BreakpointActionContinue,
+			},
+			expectOutput: []string{"Paused before step build\n"},
+		},
+		{
+			name:  "step",
+			input: "step\n",
+			expectResult: processor.BreakpointResult{
+				Action: processor.BreakpointActionStep,
+			},
+			expectStepping: true,
+		},
+		{
+			name:  "edit and print",
+			input: "input version=v2\nenv DEBUG=1\nenv invalid\np\nabort\n",
+			expectResult: processor.BreakpointResult{
+				Action: processor.BreakpointActionAbort,
+				Inputs: map[string]v1beta1.ParamValue{"version": *v1beta1.NewStructuredValues("v2")},
+				Envs:   map[string]string{"DEBUG": "1"},
+			},
+			expectOutput: []string{
+				"Expected env NAME=VALUE\n",
+				`"version": "v2"`,
+				`"DEBUG": "1"`,
+			},
+		},
+		{
+			name:         "unknown command",
+			input:        "run\nc\n",
+			expectResult: processor.BreakpointResult{Action: processor.BreakpointActionContinue},
+			expectOutput: []string{"Unknown command \"run\"\n"},
+		},
+		{
+			name:        "no input",
+			expectError: io.ErrUnexpectedEOF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terminal := &mockTerminal{}
+			out := &strings.Builder{}
+			breakpointer := Breakpoint(terminal, strings.NewReader(tt.input), out)
+
+			result, err := breakpointer.Break(context.Background(), processor.BreakpointRequest{
+				StepName: "build",
+				Position: processor.BreakpointBefore,
+				Context:  &v1beta1.Context{},
+			})
+
+			assert.Equal(t, []string{"release", "restore"}, terminal.calls)
+			for _, expect := range tt.expectOutput {
+				assert.Contains(t, out.String(), expect)
+			}
+
+			if tt.expectError != nil {
+				require.ErrorIs(t, err, tt.expectError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectResult.Action, result.Action)
+			if tt.expectResult.Inputs != nil {
+				assert.Equal(t, tt.expectResult.Inputs, result.Inputs)
+			}
+
+			if tt.expectResult.Envs != nil {
+				assert.Equal(t, tt.expectResult.Envs, result.Envs)
+			}
+
+			assert.Equal(t, tt.expectStepping, breakpointer.Stepping())
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package debug
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/internal/runtime"
+	"github.com/raffis/rageta/internal/xio"
+)
+
+// Terminal is released while a debug shell is attached to it, e.g. the terminal ui.
+type Terminal interface {
+	ReleaseTerminal() error
+	RestoreTerminal() error
+}
+
+type shell struct {
+	driver   runtime.Debugger
+	terminal Terminal
+	stdin    io.Reader
+	stdout   io.Writer
+	mu       sync.Mutex
+}
+
+// Shell debugs failed steps with an interactive shell in a copy of the failed container.
+// Once the shell exits the step is retried, skipped or the pipeline is aborted. Concurrent failures are debugged one after another.
+func Shell(driver runtime.Debugger, terminal Terminal, stdin io.Reader, stdout io.Writer) processor.Debugger {
+	return &shell{
+		driver:   driver,
+		terminal: terminal,
+		stdin:    stdin,
+		stdout:   stdout,
+	}
+}
+
+func (s *shell) Debug(ctx context.Context, request processor.DebugRequest) (processor.DebugAction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.terminal != nil {
+		if err := s.terminal.ReleaseTerminal(); err != nil {
+			return "", fmt.Errorf("failed to release terminal: %w", err)
+		}
+
+		defer func() {
+			_ = s.terminal.RestoreTerminal()
+		}()
+	}
+
+	fmt.Fprintf(s.stdout, "Step %s failed: %s\n", request.StepName, request.Error)
+	fmt.Fprintf(s.stdout, "Starting a debug shell in the failed container, exit the shell to continue.\n")
+
+	if err := s.driver.DebugPod(ctx, request.Pod, s.stdin, s.stdout); err != nil {
+		return "", err
+	}
+
+	return s.prompt(ctx, request.StepName)
+}
+
+func (s *shell) prompt(ctx context.Context, stepName string) (processor.DebugAction, error) {
+	lines, cancel, err := xio.ReadLines(ctx, s.stdin)
+	if err != nil {
+		return "", err
+	}
+
+	defer cancel()
+
+	for {
+		fmt.Fprintf(s.stdout, "Retry, skip or abort step %s? [r/s/a]: ", stepName)
+
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(s.stdout)
+			return "", ctx.Err()
+		case line, ok := <-lines:
+			if !ok {
+				return "", io.ErrUnexpectedEOF
+			}
+
+			if action, ok := parseAction(line); ok {
+				return action, nil
+			}
+		}
+	}
+}
+
+// parseAction parses the action chosen after debugging.
+func parseAction(answer string) (processor.DebugAction, bool) {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "r", "retry":
+		return processor.DebugActionRetry, true
+	case "s", "skip":
+		return processor.DebugActionSkip, true
+	case "a", "abort":
+		return processor.DebugActionAbort, true
+	}
+
+	return "", false
+}
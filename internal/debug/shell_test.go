@@ -0,0 +1,101 @@
+package debug
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/internal/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDriver struct {
+	pods []*runtime.Pod
+	err  error
+}
+
+func (d *mockDriver) DebugPod(ctx context.Context, pod *runtime.Pod, stdin io.Reader, stdout io.Writer) error {
+	d.pods = append(d.pods, pod)
+	return d.err
+}
+
+type mockTerminal struct {
+	calls []string
+}
+
+func (t *mockTerminal) ReleaseTerminal() error {
+	t.calls = append(t.calls, "release")
+	return nil
+}
+
+func (t *mockTerminal) RestoreTerminal() error {
+	t.calls = append(t.calls, "restore")
+	return nil
+}
+
+func TestShell(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		driverErr    error
+		expectAction processor.DebugAction
+		expectError  error
+	}{
+		{
+			name:         "retry",
+			input:        "r\n",
+			expectAction: processor.DebugActionRetry,
+		},
+		{
+			name:         "invalid answers are asked again",
+			input:        "maybe\nskip\n",
+			expectAction: processor.DebugActionSkip,
+		},
+		{
+			name:         "abort",
+			input:        "A\n",
+			expectAction: processor.DebugActionAbort,
+		},
+		{
+			name:        "no answer",
+			input:       "",
+			expectError: io.ErrUnexpectedEOF,
+		},
+		{
+			name:        "shell failed",
+			driverErr:   errors.New("no shell"),
+			expectError: errors.New("no shell"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driver := &mockDriver{err: tt.driverErr}
+			terminal := &mockTerminal{}
+			out := &strings.Builder{}
+			pod := &runtime.Pod{Name: "pod"}
+
+			action, err := Shell(driver, terminal, strings.NewReader(tt.input), out).Debug(context.Background(), processor.DebugRequest{
+				StepName: "build",
+				Pod:      pod,
+				Error:    errors.New("exit code 1"),
+			})
+
+			assert.Equal(t, []*runtime.Pod{pod}, driver.pods)
+			assert.Equal(t, []string{"release", "restore"}, terminal.calls)
+			assert.Contains(t, out.String(), "Step build failed: exit code 1\n")
+
+			if tt.expectError != nil {
+				require.EqualError(t, err, tt.expectError.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectAction, action)
+		})
+	}
+}
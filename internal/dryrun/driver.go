@@ -0,0 +1,71 @@
+package dryrun
+
+import (
+	"context"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/raffis/rageta/internal/runtime"
+)
+
+type driver struct{}
+
+// NewDriver returns a container runtime which does not start any containers.
+// The container specs are recorded to the step found in the context instead.
+func NewDriver() runtime.Interface {
+	return &driver{}
+}
+
+func (d *driver) CreatePod(ctx context.Context, pod *runtime.Pod, stdin io.Reader, stdout, stderr io.Writer) (runtime.Await, error) {
+	step, hasStep := StepFromContext(ctx)
+
+	for _, container := range pod.Spec.Containers {
+		pod.Status.Containers = append(pod.Status.Containers, runtime.ContainerStatus{
+			ContainerID: "dry-run",
+			Name:        container.Name,
+			Ready:       true,
+			Started:     true,
+		})
+
+		if !hasStep {
+			continue
+		}
+
+		recorded := Container{
+			Image:      container.Image,
+			PullPolicy: string(container.ImagePullPolicy),
+			Command:    container.Command,
+			Args:       container.Args,
+			WorkingDir: container.PWD,
+		}
+
+		for key := range container.Env {
+			recorded.Env = append(recorded.Env, key)
+		}
+
+		sort.Strings(recorded.Env)
+
+		for _, volume := range container.Volumes {
+			recorded.Volumes = append(recorded.Volumes, Volume{
+				Name:     volume.Name,
+				HostPath: volume.HostPath,
+				Path:     volume.Path,
+			})
+		}
+
+		step.AddContainer(recorded)
+	}
+
+	return &await{}, nil
+}
+
+func (d *driver) DeletePod(ctx context.Context, pod *runtime.Pod, timeout time.Duration) error {
+	return nil
+}
+
+type await struct{}
+
+func (a *await) Wait(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,153 @@
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type Volume struct {
+	Name     string `json:"name,omitempty"`
+	HostPath string `json:"hostPath"`
+	Path     string `json:"path"`
+}
+
+type Container struct {
+	Image      string   `json:"image"`
+	PullPolicy string   `json:"pullPolicy,omitempty"`
+	Command    []string `json:"command,omitempty"`
+	Args       []string `json:"args,omitempty"`
+	WorkingDir string   `json:"workingDir,omitempty"`
+	Env        []string `json:"env,omitempty"`
+	Volumes    []Volume `json:"volumes,omitempty"`
+}
+
+// Step is a single step execution, a step with a matrix is recorded once per combination.
+type Step struct {
+	Name       string            `json:"name"`
+	Type       string            `json:"type,omitempty"`
+	Matrix     map[string]string `json:"matrix,omitempty"`
+	If         *bool             `json:"if,omitempty"`
+	Skipped    string            `json:"skipped,omitempty"`
+	Containers []Container       `json:"containers,omitempty"`
+	mu         sync.Mutex
+}
+
+func (s *Step) AddContainer(container Container) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Containers = append(s.Containers, container)
+}
+
+// Plan records the steps in the order they would have been executed.
+type Plan struct {
+	Steps []*Step `json:"steps"`
+	mu    sync.Mutex
+}
+
+func NewPlan() *Plan {
+	return &Plan{}
+}
+
+func (p *Plan) Add(step *Step) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Steps = append(p.Steps, step)
+}
+
+type stepKey struct{}
+
+func WithStep(ctx context.Context, step *Step) context.Context {
+	return context.WithValue(ctx, stepKey{}, step)
+}
+
+func StepFromContext(ctx context.Context) (*Step, bool) {
+	step, ok := ctx.Value(stepKey{}).(*Step)
+	return step, ok
+}
+
+func (p *Plan) WriteJSON(w io.Writer) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+func (p *Plan) WriteText(w io.Writer) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var s strings.Builder
+	for i, step := range p.Steps {
+		fmt.Fprintf(&s, "%d. %s", i+1, step.Name)
+		if step.Type != "" {
+			fmt.Fprintf(&s, " (%s)", step.Type)
+		}
+
+		if len(step.Matrix) > 0 {
+			var params []string
+			for k, v := range step.Matrix {
+				params = append(params, fmt.Sprintf("%s=%s", k, v))
+			}
+
+			sort.Strings(params)
+			fmt.Fprintf(&s, " [%s]", strings.Join(params, ", "))
+		}
+
+		s.WriteString("\n")
+
+		if step.If != nil {
+			fmt.Fprintf(&s, "   if:        %t\n", *step.If)
+		}
+
+		if step.Skipped != "" {
+			fmt.Fprintf(&s, "   skipped:   %s\n", step.Skipped)
+		}
+
+		for _, container := range step.Containers {
+			fmt.Fprintf(&s, "   image:     %s\n", container.Image)
+			if cmd := append(append([]string{}, container.Command...), container.Args...); len(cmd) > 0 {
+				fmt.Fprintf(&s, "   command:   %s\n", quoteArgs(cmd))
+			}
+
+			if container.WorkingDir != "" {
+				fmt.Fprintf(&s, "   workdir:   %s\n", container.WorkingDir)
+			}
+
+			if len(container.Env) > 0 {
+				fmt.Fprintf(&s, "   env:       %s\n", strings.Join(container.Env, ", "))
+			}
+
+			for _, volume := range container.Volumes {
+				fmt.Fprintf(&s, "   volume:    %s:%s\n", volume.HostPath, volume.Path)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, s.String())
+	return err
+}
+
+func quoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+			quoted[i] = fmt.Sprintf("%q", arg)
+			continue
+		}
+
+		quoted[i] = arg
+	}
+
+	return strings.Join(quoted, " ")
+}
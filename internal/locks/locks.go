@@ -0,0 +1,104 @@
+package locks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// Locker acquires weighted named semaphores.
+type Locker interface {
+	// TryAcquire acquires weight units of the semaphore without blocking, ok is false if not enough units are available.
+	TryAcquire(name string, capacity, weight int) (release func() error, ok bool, err error)
+	// Acquire blocks until weight units of the semaphore are acquired or the context is done.
+	Acquire(ctx context.Context, name string, capacity, weight int) (release func() error, err error)
+}
+
+type fileLocker struct {
+	dir          string
+	pollInterval time.Duration
+}
+
+// File returns a locker backed by lock files in dir.
+// A semaphore consists of one lock file per unit of its capacity, it is shared with all processes using the same dir on the host.
+func File(dir string, pollInterval time.Duration) Locker {
+	return &fileLocker{
+		dir:          dir,
+		pollInterval: pollInterval,
+	}
+}
+
+var invalidChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (l *fileLocker) slot(name string, i int) string {
+	return filepath.Join(l.dir, fmt.Sprintf("%s.%d.lock", invalidChars.ReplaceAllString(name, "_"), i))
+}
+
+func (l *fileLocker) TryAcquire(name string, capacity, weight int) (func() error, bool, error) {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	if weight < 1 {
+		weight = 1
+	}
+
+	if weight > capacity {
+		return nil, false, fmt.Errorf("weight %d of %q exceeds its capacity of %d", weight, name, capacity)
+	}
+
+	if err := os.MkdirAll(l.dir, 0750); err != nil {
+		return nil, false, fmt.Errorf("failed to create lock dir: %w", err)
+	}
+
+	var acquired []*flock.Flock
+	release := func() error {
+		var errs []error
+		for _, lock := range acquired {
+			errs = append(errs, lock.Unlock())
+		}
+
+		return errors.Join(errs...)
+	}
+
+	for i := 0; i < capacity && len(acquired) < weight; i++ {
+		lock := flock.New(l.slot(name, i))
+		ok, err := lock.TryLock()
+		if err != nil {
+			_ = release()
+			return nil, false, fmt.Errorf("failed to lock %q: %w", name, err)
+		}
+
+		if ok {
+			acquired = append(acquired, lock)
+		}
+	}
+
+	// Partially acquired units are released to not block others while waiting for the remaining ones
+	if len(acquired) < weight {
+		return nil, false, release()
+	}
+
+	return release, true, nil
+}
+
+func (l *fileLocker) Acquire(ctx context.Context, name string, capacity, weight int) (func() error, error) {
+	for {
+		release, ok, err := l.TryAcquire(name, capacity, weight)
+		if err != nil || ok {
+			return release, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(l.pollInterval):
+		}
+	}
+}
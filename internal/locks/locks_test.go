@@ -0,0 +1,94 @@
+package locks
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Mutex(t *testing.T) {
+	locker := File(t.TempDir(), time.Millisecond)
+
+	release, ok, err := locker.TryAcquire("db-migrations", 1, 1)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, ok, err = locker.TryAcquire("db-migrations", 1, 1)
+	require.NoError(t, err)
+	assert.False(t, ok, "lock is held")
+
+	_, ok, err = locker.TryAcquire("other", 1, 1)
+	require.NoError(t, err)
+	assert.True(t, ok, "other locks are independent")
+
+	require.NoError(t, release())
+
+	_, ok, err = locker.TryAcquire("db-migrations", 1, 1)
+	require.NoError(t, err)
+	assert.True(t, ok, "lock is released")
+}
+
+func TestFile_Semaphore(t *testing.T) {
+	locker := File(t.TempDir(), time.Millisecond)
+
+	_, ok, err := locker.TryAcquire("docker", 3, 2)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, ok, err = locker.TryAcquire("docker", 3, 2)
+	require.NoError(t, err)
+	assert.False(t, ok, "only a single unit is left")
+
+	release, ok, err := locker.TryAcquire("docker", 3, 1)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	require.NoError(t, release())
+
+	_, _, err = locker.TryAcquire("docker", 3, 4)
+	assert.EqualError(t, err, `weight 4 of "docker" exceeds its capacity of 3`)
+}
+
+func TestFile_Acquire(t *testing.T) {
+	locker := File(t.TempDir(), time.Millisecond)
+
+	var running, maxRunning atomic.Int32
+	var wg sync.WaitGroup
+
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := locker.Acquire(context.Background(), "semaphore", 2, 1)
+			require.NoError(t, err)
+
+			n := running.Add(1)
+			for {
+				current := maxRunning.Load()
+				if n <= current || maxRunning.CompareAndSwap(current, n) {
+					break
+				}
+			}
+
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			require.NoError(t, release())
+		}()
+	}
+
+	wg.Wait()
+	assert.LessOrEqual(t, maxRunning.Load(), int32(2))
+
+	_, ok, err := locker.TryAcquire("semaphore", 1, 1)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = locker.Acquire(ctx, "semaphore", 1, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -35,29 +35,55 @@ func (o *Options) BindFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.Provider, "provider", o.Provider, "OCI provider type")
 }
 
-func (o *Options) Build(ctx context.Context) (*oci.Client, error) {
-	ref, err := name.ParseReference(o.URL)
-	if err != nil {
-		return nil, err
-	}
-
-	var auth authn.Authenticator
-	opts := oci.DefaultOptions()
-
+func (o *Options) authenticator(ctx context.Context) (authn.Authenticator, error) {
 	if o.Provider == "generic" && o.Creds != "" {
-		auth, err = oci.GetAuthFromCredentials(o.Creds)
+		auth, err := oci.GetAuthFromCredentials(o.Creds)
 		if err != nil {
 			return nil, fmt.Errorf("could not login with credentials: %w", err)
 		}
-		opts = append(opts, crane.WithAuth(auth))
+
+		return auth, nil
 	}
 
 	if o.Provider != "generic" {
-		auth, err = authutils.GetArtifactRegistryCredentials(ctx, o.Provider, o.URL)
+		auth, err := authutils.GetArtifactRegistryCredentials(ctx, o.Provider, o.URL)
 		if err != nil {
 			return nil, fmt.Errorf("error during login with provider: %w", err)
 		}
 
+		return auth, nil
+	}
+
+	return nil, nil
+}
+
+// RemoteOptions returns the registry options for direct registry access, e.g. to lookup artifact signatures.
+func (o *Options) RemoteOptions(ctx context.Context) ([]remote.Option, error) {
+	auth, err := o.authenticator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if auth == nil {
+		return []remote.Option{remote.WithAuthFromKeychain(authn.DefaultKeychain), remote.WithContext(ctx)}, nil
+	}
+
+	return []remote.Option{remote.WithAuth(auth), remote.WithContext(ctx)}, nil
+}
+
+func (o *Options) Build(ctx context.Context) (*oci.Client, error) {
+	ref, err := name.ParseReference(o.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := oci.DefaultOptions()
+	auth, err := o.authenticator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if auth != nil {
 		opts = append(opts, crane.WithAuth(auth))
 	}
 
@@ -43,18 +43,23 @@ func UI(sender sender) processor.OutputFactory {
 			case errors.Is(err, processor.ErrAllowFailure):
 				sender.Send(tui.StepMsg{
 					Name:   uniqueName,
-					Status: tui.StepStatusSkipped,
+					Status: tui.StepStatusWarning,
 				})
-			case errors.Is(err, processor.ErrConditionFalse):
+			case errors.Is(err, processor.ErrConditionFalse), errors.Is(err, processor.ErrUnchanged), errors.Is(err, processor.ErrSwitchCaseSkipped):
 				sender.Send(tui.StepMsg{
 					Name:   uniqueName,
 					Status: tui.StepStatusSkipped,
 				})
-			case errors.Is(err, processor.ErrSkipDone):
+			case errors.Is(err, processor.ErrSkipDone), errors.Is(err, processor.ErrSkipBlacklist), errors.Is(err, processor.ErrSkipDebug):
 				sender.Send(tui.StepMsg{
 					Name:   uniqueName,
 					Status: tui.StepStatusSkipped,
 				})
+			case errors.Is(err, processor.ErrTimeout):
+				sender.Send(tui.StepMsg{
+					Name:   uniqueName,
+					Status: tui.StepStatusTimeout,
+				})
 			default:
 				sender.Send(tui.StepMsg{
 					Name:   uniqueName,
@@ -66,3 +71,18 @@ func UI(sender sender) processor.OutputFactory {
 		}
 	}
 }
+
+// UIBlocked marks steps as blocked in the terminal ui while they wait for a lock.
+func UIBlocked(sender sender) processor.BlockedNotifier {
+	return func(ctx processor.StepContext, blocked bool) {
+		status := tui.StepStatusRunning
+		if blocked {
+			status = tui.StepStatusBlocked
+		}
+
+		sender.Send(tui.StepMsg{
+			Name:   ctx.UniqueName(),
+			Status: status,
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package pipeline
 
 import (
+	"context"
 	"os"
 
 	"github.com/go-logr/logr"
@@ -87,7 +88,7 @@ func (e *builder) mapInputs(params []v1beta1.InputParam, inputs map[string]v1bet
 	return result, nil
 }
 
-func (e *builder) Build(pipeline v1beta1.Pipeline, entrypointName string, inputs map[string]v1beta1.ParamValue, stepCtx processor.StepContext) (processor.Executable, error) {
+func (e *builder) Build(pipeline v1beta1.Pipeline, entrypointName string, inputs map[string]v1beta1.ParamValue, stepCtx processor.StepContext, opts ...processor.BuildOption) (processor.Executable, error) {
 	pipeline.SetDefaults()
 
 	mappedInputs, err := e.mapInputs(pipeline.Inputs, inputs)
@@ -117,6 +118,10 @@ func (e *builder) Build(pipeline v1beta1.Pipeline, entrypointName string, inputs
 		stepCtx.ContextDir = contextDir
 		stepCtx.Containers = make(map[string]runtime.ContainerStatus)
 		stepCtx.Steps = make(map[string]*processor.StepContext)
+		for _, opt := range opts {
+			opt(&stepCtx)
+		}
+
 		stepCtx.InputVars.Inputs = mappedInputs
 		outputs := make(map[string]v1beta1.ParamValue)
 
@@ -127,7 +132,17 @@ func (e *builder) Build(pipeline v1beta1.Pipeline, entrypointName string, inputs
 			}
 		}*/
 
-		stepCtx, pipelineErr := entrypoint(stepCtx)
+		// The pipeline deadline propagates to all steps through the context
+		parentCtx := stepCtx.Context
+		entrypointCtx := stepCtx
+		if pipeline.Timeout.Duration > 0 {
+			ctx, cancel := context.WithTimeout(stepCtx.Context, pipeline.Timeout.Duration)
+			defer cancel()
+			entrypointCtx.Context = ctx
+		}
+
+		stepCtx, pipelineErr := entrypoint(entrypointCtx)
+		stepCtx.Context = parentCtx
 
 		for _, pipelineOutput := range pipeline.Outputs {
 			if _, ok := stepCtx.Steps[pipelineOutput.Step.Name]; !ok {
@@ -29,7 +29,7 @@ func TestResolveExtends_SimpleExtends(t *testing.T) {
 			},
 		},
 		StepOptions: v1beta1.StepOptions{
-			AllowFailure: true,
+			AllowFailure: &v1beta1.AllowFailure{Always: true},
 			Env: []v1beta1.EnvVar{
 				{Name: "FOO", Value: strPtr("bar")},
 			},
@@ -55,7 +55,7 @@ func TestResolveExtends_SimpleExtends(t *testing.T) {
 	assert.Nil(t, child.Extends, "Extends should be cleared after resolution")
 	assert.Equal(t, "alpine", child.Run.Image)
 	assert.Equal(t, "echo base", child.Run.Script)
-	assert.True(t, child.AllowFailure)
+	assert.True(t, child.AllowFailure.Always)
 	// Env from the Extendsing step overrides (merge patch replaces arrays)
 	assert.Equal(t, []v1beta1.EnvVar{{Name: "EXTRA", Value: strPtr("val")}}, child.Env)
 }
@@ -92,7 +92,7 @@ func TestResolveExtends_ChainedExtends(t *testing.T) {
 		Name: "grandparent",
 		Run:  &v1beta1.RunStep{Container: v1beta1.Container{Image: "alpine"}},
 		StepOptions: v1beta1.StepOptions{
-			AllowFailure: true,
+			AllowFailure: &v1beta1.AllowFailure{Always: true},
 		},
 	}
 
@@ -119,7 +119,7 @@ func TestResolveExtends_ChainedExtends(t *testing.T) {
 	assert.Equal(t, "child", childStep.Name)
 	assert.Nil(t, childStep.Extends)
 	assert.Equal(t, "alpine", childStep.Run.Image)
-	assert.True(t, childStep.AllowFailure)
+	assert.True(t, childStep.AllowFailure.Always)
 	assert.Equal(t, []v1beta1.EnvVar{{Name: "LEVEL", Value: strPtr("child")}}, childStep.Env)
 }
 
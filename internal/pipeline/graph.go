@@ -0,0 +1,382 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/raffis/rageta/internal/provider"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+type EdgeKind string
+
+var (
+	// EdgeSequence references a step of an and step, executed one after another.
+	EdgeSequence EdgeKind = "sequence"
+	// EdgeParallel references a step of a concurrent step.
+	EdgeParallel EdgeKind = "parallel"
+	// EdgePipe references a step of a pipe step, the stdout is streamed to the next one.
+	EdgePipe EdgeKind = "pipe"
+	// EdgeNeeds references a step which must be finished before the step is executed.
+	EdgeNeeds EdgeKind = "needs"
+	// EdgeInherit references the entrypoint of an expanded inherited pipeline.
+	EdgeInherit EdgeKind = "inherit"
+	// EdgeMatrix references a single combination of an expanded matrix.
+	EdgeMatrix EdgeKind = "matrix"
+	// EdgeCase references a step of a switch case, the order is the position of the case.
+	EdgeCase EdgeKind = "case"
+	// EdgeLoop references a step of a forEach step, executed sequentially once per iteration.
+	EdgeLoop EdgeKind = "loop"
+)
+
+type Node struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Type       string            `json:"type"`
+	Pipeline   string            `json:"pipeline,omitempty"`
+	Image      string            `json:"image,omitempty"`
+	Matrix     map[string]string `json:"matrix,omitempty"`
+	Entrypoint bool              `json:"entrypoint,omitempty"`
+}
+
+// Edge points from the referencing step to the referenced step.
+type Edge struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Kind  EdgeKind `json:"kind"`
+	Order int      `json:"order,omitempty"`
+}
+
+type Graph struct {
+	Pipeline string `json:"pipeline,omitempty"`
+	Nodes    []Node `json:"nodes"`
+	Edges    []Edge `json:"edges"`
+}
+
+type graphBuilder struct {
+	provider     provider.Interface
+	expandMatrix bool
+	maxDepth     int
+	graph        Graph
+}
+
+type GraphOption func(*graphBuilder)
+
+// WithExpandInherit resolves the pipelines of inherit steps using the provider and adds their steps to the graph.
+func WithExpandInherit(provider provider.Interface) GraphOption {
+	return func(b *graphBuilder) {
+		b.provider = provider
+	}
+}
+
+// WithExpandMatrix adds a node for each combination of a matrix which does not depend on runtime values.
+func WithExpandMatrix() GraphOption {
+	return func(b *graphBuilder) {
+		b.expandMatrix = true
+	}
+}
+
+// NewGraph builds the execution graph of a pipeline.
+func NewGraph(ctx context.Context, pipeline v1beta1.Pipeline, opts ...GraphOption) (Graph, error) {
+	b := &graphBuilder{
+		maxDepth: 10,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	b.graph.Pipeline = pipeline.Name
+	if _, err := b.add(ctx, pipeline, "", []string{pipeline.Name}); err != nil {
+		return b.graph, err
+	}
+
+	return b.graph, nil
+}
+
+// add adds all steps of the pipeline with the given id prefix and returns the id of the entrypoint.
+func (b *graphBuilder) add(ctx context.Context, pipeline v1beta1.Pipeline, prefix string, refs []string) (string, error) {
+	steps, err := resolveExtends(pipeline.Steps)
+	if err != nil {
+		return "", err
+	}
+
+	entrypoint := pipeline.Entrypoint
+	if entrypoint == "" && len(steps) > 0 {
+		entrypoint = steps[0].Name
+	}
+
+	known := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		known[step.Name] = true
+	}
+
+	for _, step := range steps {
+		id := prefix + step.Name
+		types := stepTypes(step)
+		node := Node{
+			ID:         id,
+			Name:       step.Name,
+			Entrypoint: prefix == "" && step.Name == entrypoint,
+		}
+
+		if len(types) > 0 {
+			node.Type = types[0]
+		}
+
+		if step.Run != nil {
+			node.Image = step.Run.Image
+		}
+
+		if step.Inherit != nil {
+			node.Pipeline = step.Inherit.Pipeline
+		}
+
+		b.graph.Nodes = append(b.graph.Nodes, node)
+
+		for n, list := range stepRefs(step) {
+			for i, ref := range list.refs {
+				if !known[ref.Name] {
+					continue
+				}
+
+				edge := Edge{From: id, To: prefix + ref.Name, Kind: list.kind}
+				switch list.kind {
+				case EdgeSequence, EdgePipe, EdgeLoop:
+					edge.Order = i + 1
+				case EdgeCase:
+					edge.Order = n + 1
+				}
+
+				b.graph.Edges = append(b.graph.Edges, edge)
+			}
+		}
+
+		if b.expandMatrix {
+			b.addMatrix(id, step)
+		}
+
+		if b.provider != nil && step.Inherit != nil {
+			if err := b.addInherit(ctx, id, *step.Inherit, refs); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return prefix + entrypoint, nil
+}
+
+func (b *graphBuilder) addInherit(ctx context.Context, id string, inherit v1beta1.InheritStep, refs []string) error {
+	// References with substitutions and generated pipelines are only known at runtime
+	if inherit.Generated != nil || strings.Contains(inherit.Pipeline, "$(") || slices.Contains(refs, inherit.Pipeline) || len(refs) > b.maxDepth {
+		return nil
+	}
+
+	pipeline, err := b.provider.Resolve(ctx, inherit.Pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to resolve inherited pipeline %q: %w", inherit.Pipeline, err)
+	}
+
+	if inherit.Entrypoint != "" {
+		pipeline.Entrypoint = inherit.Entrypoint
+	}
+
+	entrypoint, err := b.add(ctx, pipeline, id+"/", append(refs, inherit.Pipeline))
+	if err != nil {
+		return err
+	}
+
+	b.graph.Edges = append(b.graph.Edges, Edge{From: id, To: entrypoint, Kind: EdgeInherit})
+	return nil
+}
+
+func (b *graphBuilder) addMatrix(id string, step v1beta1.Step) {
+	if step.Matrix == nil || len(step.Matrix.Params) == 0 {
+		return
+	}
+
+	for _, combination := range staticMatrix(step.Matrix.Params) {
+		var values []string
+		for _, param := range step.Matrix.Params {
+			values = append(values, fmt.Sprintf("%s=%s", param.Name, combination[param.Name]))
+		}
+
+		node := Node{
+			ID:     fmt.Sprintf("%s[%s]", id, strings.Join(values, ",")),
+			Name:   fmt.Sprintf("%s[%s]", step.Name, strings.Join(values, ",")),
+			Type:   "matrix",
+			Matrix: combination,
+		}
+
+		b.graph.Nodes = append(b.graph.Nodes, node)
+		b.graph.Edges = append(b.graph.Edges, Edge{From: id, To: node.ID, Kind: EdgeMatrix})
+	}
+}
+
+// staticMatrix returns all combinations of the matrix params in declaration order.
+// No combinations are returned if any param depends on a substitution.
+func staticMatrix(params []v1beta1.Param) []map[string]string {
+	combinations := []map[string]string{{}}
+
+	for _, param := range params {
+		var values []string
+		switch param.Value.Type {
+		case v1beta1.ParamTypeString:
+			values = []string{param.Value.StringVal}
+		case v1beta1.ParamTypeArray:
+			values = param.Value.ArrayVal
+		}
+
+		var next []map[string]string
+		for _, combination := range combinations {
+			for _, value := range values {
+				if strings.Contains(value, "$(") {
+					return nil
+				}
+
+				extended := maps.Clone(combination)
+				extended[param.Name] = value
+				next = append(next, extended)
+			}
+		}
+
+		combinations = next
+	}
+
+	return combinations
+}
+
+var dotShapes = map[string]string{
+	"run":        "box",
+	"inherit":    "component",
+	"and":        "oval",
+	"pipe":       "cds",
+	"concurrent": "parallelogram",
+	"matrix":     "note",
+	"switch":     "diamond",
+	"forEach":    "doubleoctagon",
+}
+
+var dotEdgeStyles = map[EdgeKind]string{
+	EdgeSequence: "solid",
+	EdgeParallel: "dashed",
+	EdgePipe:     "bold",
+	EdgeNeeds:    "dotted",
+	EdgeInherit:  "solid",
+	EdgeMatrix:   "dashed",
+	EdgeCase:     "solid",
+	EdgeLoop:     "solid",
+}
+
+// DOT renders the graph in the graphviz dot language.
+func (g Graph) DOT() string {
+	var s strings.Builder
+	fmt.Fprintf(&s, "digraph %s {\n", dotQuote(g.Pipeline))
+	s.WriteString("  rankdir=LR;\n")
+
+	for _, node := range g.Nodes {
+		shape, ok := dotShapes[node.Type]
+		if !ok {
+			shape = "box"
+		}
+
+		label := node.Name
+		if node.Type != "" {
+			label = fmt.Sprintf("%s\n(%s)", node.Name, node.Type)
+		}
+
+		attrs := fmt.Sprintf("label=%s, shape=%s", dotQuote(label), shape)
+		if node.Entrypoint {
+			attrs += ", penwidth=2"
+		}
+
+		fmt.Fprintf(&s, "  %s [%s];\n", dotQuote(node.ID), attrs)
+	}
+
+	for _, edge := range g.Edges {
+		label := string(edge.Kind)
+		if edge.Order > 0 {
+			label = fmt.Sprintf("%s %d", edge.Kind, edge.Order)
+		}
+
+		fmt.Fprintf(&s, "  %s -> %s [label=%s, style=%s];\n", dotQuote(edge.From), dotQuote(edge.To), dotQuote(label), dotEdgeStyles[edge.Kind])
+	}
+
+	s.WriteString("}\n")
+	return s.String()
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+var mermaidShapes = map[string][2]string{
+	"run":        {`["`, `"]`},
+	"inherit":    {`[["`, `"]]`},
+	"and":        {`(["`, `"])`},
+	"pipe":       {`>"`, `"]`},
+	"concurrent": {`{{"`, `"}}`},
+	"matrix":     {`("`, `")`},
+	"switch":     {`{"`, `"}`},
+	"forEach":    {`[/"`, `"/]`},
+}
+
+var mermaidArrows = map[EdgeKind]string{
+	EdgeSequence: "-->",
+	EdgeParallel: "-.->",
+	EdgePipe:     "==>",
+	EdgeNeeds:    "-.->",
+	EdgeInherit:  "-->",
+	EdgeMatrix:   "-.->",
+	EdgeCase:     "-->",
+	EdgeLoop:     "-->",
+}
+
+// Mermaid renders the graph as mermaid flowchart.
+func (g Graph) Mermaid() string {
+	var s strings.Builder
+	s.WriteString("flowchart LR\n")
+
+	// Mermaid ids may not contain most special characters, nodes are referenced by their index instead
+	ids := make(map[string]string, len(g.Nodes))
+	for i, node := range g.Nodes {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+
+		shape, ok := mermaidShapes[node.Type]
+		if !ok {
+			shape = mermaidShapes["run"]
+		}
+
+		label := mermaidEscape(node.Name)
+		if node.Type != "" {
+			label = fmt.Sprintf("%s<br/><i>%s</i>", label, node.Type)
+		}
+
+		if node.Entrypoint {
+			label = "<b>" + label + "</b>"
+		}
+
+		fmt.Fprintf(&s, "  %s%s%s%s\n", ids[node.ID], shape[0], label, shape[1])
+	}
+
+	for _, edge := range g.Edges {
+		label := string(edge.Kind)
+		if edge.Order > 0 {
+			label = fmt.Sprintf("%s %d", edge.Kind, edge.Order)
+		}
+
+		fmt.Fprintf(&s, "  %s %s|%s| %s\n", ids[edge.From], mermaidArrows[edge.Kind], label, ids[edge.To])
+	}
+
+	return s.String()
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
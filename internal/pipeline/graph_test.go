@@ -0,0 +1,168 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const graphManifest = `apiVersion: core.rageta.io/v1beta1
+kind: Pipeline
+metadata:
+  name: build
+steps:
+- name: main
+  and:
+    refs:
+    - name: lint
+    - name: tests
+- name: lint
+  pipe:
+    refs:
+    - name: golangci
+    - name: report
+- name: golangci
+  run:
+    image: golangci/golangci-lint
+- name: report
+  inherit:
+    pipeline: ghcr.io/org/report:v1
+- name: tests
+  needs:
+  - name: golangci
+  concurrent:
+    refs:
+    - name: unit
+- name: unit
+  matrix:
+    params:
+    - name: go
+      value: ["1.24", "1.25"]
+  run:
+    image: golang:$(context.matrix.go)
+`
+
+type mockGraphProvider struct {
+	pipelines map[string]v1beta1.Pipeline
+}
+
+func (m *mockGraphProvider) Resolve(ctx context.Context, ref string) (v1beta1.Pipeline, error) {
+	pipeline, ok := m.pipelines[ref]
+	if !ok {
+		return pipeline, errors.New("not found")
+	}
+
+	return pipeline, nil
+}
+
+func (m *mockGraphProvider) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestNewGraph(t *testing.T) {
+	graph, err := NewGraph(context.Background(), decodeManifest(t, graphManifest))
+	require.NoError(t, err)
+
+	assert.Equal(t, "build", graph.Pipeline)
+	assert.Equal(t, []Node{
+		{ID: "main", Name: "main", Type: "and", Entrypoint: true},
+		{ID: "lint", Name: "lint", Type: "pipe"},
+		{ID: "golangci", Name: "golangci", Type: "run", Image: "golangci/golangci-lint"},
+		{ID: "report", Name: "report", Type: "inherit", Pipeline: "ghcr.io/org/report:v1"},
+		{ID: "tests", Name: "tests", Type: "concurrent"},
+		{ID: "unit", Name: "unit", Type: "run", Image: "golang:$(context.matrix.go)"},
+	}, graph.Nodes)
+
+	assert.Equal(t, []Edge{
+		{From: "main", To: "lint", Kind: EdgeSequence, Order: 1},
+		{From: "main", To: "tests", Kind: EdgeSequence, Order: 2},
+		{From: "lint", To: "golangci", Kind: EdgePipe, Order: 1},
+		{From: "lint", To: "report", Kind: EdgePipe, Order: 2},
+		{From: "tests", To: "unit", Kind: EdgeParallel},
+		{From: "tests", To: "golangci", Kind: EdgeNeeds},
+	}, graph.Edges)
+}
+
+func TestNewGraph_Switch(t *testing.T) {
+	graph, err := NewGraph(context.Background(), decodeManifest(t, `
+steps:
+- name: deploy
+  switch:
+    cases:
+    - celExpression: context.inputs.env == "prod"
+      refs:
+      - name: prod
+    default:
+      refs:
+      - name: dev
+- name: prod
+  run:
+    image: alpine
+- name: dev
+  run:
+    image: alpine
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "switch", graph.Nodes[0].Type)
+	assert.Equal(t, []Edge{
+		{From: "deploy", To: "prod", Kind: EdgeCase, Order: 1},
+		{From: "deploy", To: "dev", Kind: EdgeCase, Order: 2},
+	}, graph.Edges)
+}
+
+func TestNewGraph_Expand(t *testing.T) {
+	provider := &mockGraphProvider{
+		pipelines: map[string]v1beta1.Pipeline{
+			"ghcr.io/org/report:v1": decodeManifest(t, `
+steps:
+- name: upload
+  run:
+    image: alpine
+`),
+		},
+	}
+
+	graph, err := NewGraph(context.Background(), decodeManifest(t, graphManifest), WithExpandInherit(provider), WithExpandMatrix())
+	require.NoError(t, err)
+
+	assert.Contains(t, graph.Nodes, Node{ID: "report/upload", Name: "upload", Type: "run", Image: "alpine"})
+	assert.Contains(t, graph.Nodes, Node{ID: "unit[go=1.24]", Name: "unit[go=1.24]", Type: "matrix", Matrix: map[string]string{"go": "1.24"}})
+	assert.Contains(t, graph.Nodes, Node{ID: "unit[go=1.25]", Name: "unit[go=1.25]", Type: "matrix", Matrix: map[string]string{"go": "1.25"}})
+	assert.Contains(t, graph.Edges, Edge{From: "report", To: "report/upload", Kind: EdgeInherit})
+	assert.Contains(t, graph.Edges, Edge{From: "unit", To: "unit[go=1.24]", Kind: EdgeMatrix})
+
+	_, err = NewGraph(context.Background(), decodeManifest(t, graphManifest), WithExpandInherit(&mockGraphProvider{}))
+	require.Error(t, err)
+}
+
+func TestGraph_Render(t *testing.T) {
+	graph := Graph{
+		Pipeline: "build",
+		Nodes: []Node{
+			{ID: "main", Name: "main", Type: "and", Entrypoint: true},
+			{ID: "test", Name: `say "hi"`, Type: "run"},
+		},
+		Edges: []Edge{
+			{From: "main", To: "test", Kind: EdgeSequence, Order: 1},
+		},
+	}
+
+	assert.Equal(t, `digraph "build" {
+  rankdir=LR;
+  "main" [label="main\n(and)", shape=oval, penwidth=2];
+  "test" [label="say \"hi\"\n(run)", shape=box];
+  "main" -> "test" [label="sequence 1", style=solid];
+}
+`, graph.DOT())
+
+	assert.Equal(t, `flowchart LR
+  n0(["<b>main<br/><i>and</i></b>"])
+  n1["say #quot;hi#quot;<br/><i>run</i>"]
+  n0 -->|sequence 1| n1
+`, graph.Mermaid())
+}
@@ -0,0 +1,346 @@
+package pipeline
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+var stepReference = regexp.MustCompile(`context\.steps(?:\.([\w-]+)|\[["']([^"']+)["']\])`)
+
+// StepsByTags returns the names of all steps which match any of the tag selectors.
+// A selector is either `name=value` or `name` which matches any value of the tag.
+func StepsByTags(pipeline v1beta1.Pipeline, selectors []string) []string {
+	var steps []string
+	for _, step := range pipeline.Steps {
+		if slices.ContainsFunc(step.Tags, func(tag v1beta1.Tag) bool {
+			return slices.ContainsFunc(selectors, func(selector string) bool {
+				name, value, hasValue := strings.Cut(selector, "=")
+				return tag.Name == name && (!hasValue || tag.Value == value)
+			})
+		}) {
+			steps = append(steps, step.Name)
+		}
+	}
+
+	return steps
+}
+
+// Select reduces the pipeline to the target steps and the steps they depend on.
+// Targets are executed entirely while their dependencies are resolved from needs, preceding steps of and and pipe chains
+// and output references. Dependencies reported as done are not selected, their results are expected to be recovered.
+// Steps which contain selected steps are kept but only execute the selected ones.
+func Select(pipeline v1beta1.Pipeline, entrypoint string, targets []string, done func(name string) bool) (v1beta1.Pipeline, error) {
+	steps, err := resolveExtends(pipeline.Steps)
+	if err != nil {
+		return pipeline, err
+	}
+
+	stepMap, parents := indexSteps(steps)
+	s := &selector{
+		steps:    stepMap,
+		parents:  parents,
+		done:     done,
+		selected: make(map[string]bool),
+	}
+
+	for _, target := range targets {
+		if _, ok := s.steps[target]; !ok {
+			return pipeline, fmt.Errorf("unknown step %q", target)
+		}
+
+		s.add(target, true)
+	}
+
+	if entrypoint == "" {
+		entrypoint = pipeline.Entrypoint
+	}
+
+	if entrypoint == "" && len(pipeline.Steps) > 0 {
+		entrypoint = pipeline.Steps[0].Name
+	}
+
+	result := *pipeline.DeepCopy()
+	result.Steps = nil
+	for _, step := range pipeline.Steps {
+		if _, ok := s.selected[step.Name]; ok {
+			result.Steps = append(result.Steps, s.prune(*step.DeepCopy()))
+		}
+	}
+
+	reachable := s.reachable(entrypoint)
+	for _, target := range targets {
+		if !reachable[target] {
+			return pipeline, fmt.Errorf("step %q is not reachable from entrypoint %q", target, entrypoint)
+		}
+	}
+
+	return result, nil
+}
+
+type selector struct {
+	steps   map[string]v1beta1.Step
+	parents map[string][]string
+	done    func(name string) bool
+	// selected maps the selected steps to whether they are executed entirely
+	selected map[string]bool
+}
+
+func (s *selector) add(name string, entirely bool) {
+	step, ok := s.steps[name]
+	if !ok {
+		return
+	}
+
+	if current, ok := s.selected[name]; ok && (current || !entirely) {
+		return
+	}
+
+	s.selected[name] = entirely
+
+	if entirely {
+		for _, ref := range children(step) {
+			s.add(ref.Name, true)
+		}
+	}
+
+	for _, dependency := range dependencies(step) {
+		s.dependency(dependency)
+	}
+
+	for _, parentName := range s.parents[name] {
+		parent := s.steps[parentName]
+
+		if parent.And != nil {
+			for _, ref := range preceding(parent.And.Refs, name) {
+				s.dependency(ref.Name)
+			}
+		}
+
+		// Piped steps are streamed and can't be recovered
+		if parent.Pipe != nil {
+			for _, ref := range preceding(parent.Pipe.Refs, name) {
+				s.add(ref.Name, true)
+			}
+		}
+
+		s.add(parentName, false)
+	}
+}
+
+func (s *selector) dependency(name string) {
+	if s.done != nil && s.done(name) {
+		return
+	}
+
+	s.add(name, true)
+}
+
+// prune removes all references to steps which are not selected.
+func (s *selector) prune(step v1beta1.Step) v1beta1.Step {
+	keep := func(refs []v1beta1.StepReference) []v1beta1.StepReference {
+		return slices.DeleteFunc(refs, func(ref v1beta1.StepReference) bool {
+			_, ok := s.selected[ref.Name]
+			return !ok
+		})
+	}
+
+	step.Needs = keep(step.Needs)
+	if step.And != nil {
+		step.And.Refs = keep(step.And.Refs)
+	}
+	if step.Pipe != nil {
+		step.Pipe.Refs = keep(step.Pipe.Refs)
+	}
+	if step.Concurrent != nil {
+		step.Concurrent.Refs = keep(step.Concurrent.Refs)
+	}
+	if step.Switch != nil {
+		for i := range step.Switch.Cases {
+			step.Switch.Cases[i].Refs = keep(step.Switch.Cases[i].Refs)
+		}
+		if step.Switch.Default != nil {
+			step.Switch.Default.Refs = keep(step.Switch.Default.Refs)
+		}
+	}
+	if step.ForEach != nil {
+		step.ForEach.Refs = keep(step.ForEach.Refs)
+	}
+
+	return step
+}
+
+// reachable returns all selected steps which are executed from the entrypoint.
+func (s *selector) reachable(entrypoint string) map[string]bool {
+	reachable := make(map[string]bool)
+
+	var walk func(name string)
+	walk = func(name string) {
+		if _, ok := s.selected[name]; !ok || reachable[name] {
+			return
+		}
+
+		reachable[name] = true
+		step := s.steps[name]
+		for _, list := range stepRefs(s.prune(*step.DeepCopy())) {
+			for _, ref := range list.refs {
+				walk(ref.Name)
+			}
+		}
+	}
+
+	walk(entrypoint)
+	return reachable
+}
+
+// Invalidate returns the given steps and all steps whose result depends on them in pipeline order.
+// Steps containing invalidated steps are invalidated as well while their other steps are not.
+func Invalidate(pipeline v1beta1.Pipeline, names []string) ([]string, error) {
+	steps, err := resolveExtends(pipeline.Steps)
+	if err != nil {
+		return nil, err
+	}
+
+	stepMap, parents := indexSteps(steps)
+	dependents := make(map[string][]string)
+	for _, step := range steps {
+		for _, dependency := range dependencies(step) {
+			dependents[dependency] = append(dependents[dependency], step.Name)
+		}
+	}
+
+	invalid := make(map[string]bool)
+
+	var invalidate func(name string, entirely bool)
+	invalidate = func(name string, entirely bool) {
+		step, ok := stepMap[name]
+		if !ok {
+			return
+		}
+
+		if current, ok := invalid[name]; ok && (current || !entirely) {
+			return
+		}
+
+		invalid[name] = entirely
+
+		if entirely {
+			for _, ref := range children(step) {
+				invalidate(ref.Name, true)
+			}
+		}
+
+		for _, dependent := range dependents[name] {
+			invalidate(dependent, true)
+		}
+
+		for _, parentName := range parents[name] {
+			parent := stepMap[parentName]
+
+			if parent.And != nil {
+				for _, ref := range following(parent.And.Refs, name) {
+					invalidate(ref.Name, true)
+				}
+			}
+
+			// Piped steps are streamed into each other and are executed again together
+			if parent.Pipe != nil {
+				for _, ref := range parent.Pipe.Refs {
+					invalidate(ref.Name, true)
+				}
+			}
+
+			invalidate(parentName, false)
+		}
+	}
+
+	for _, name := range names {
+		invalidate(name, true)
+	}
+
+	var result []string
+	for _, step := range steps {
+		if _, ok := invalid[step.Name]; ok {
+			result = append(result, step.Name)
+		}
+	}
+
+	return result, nil
+}
+
+// indexSteps maps the steps by name and lists the steps containing each step.
+func indexSteps(steps []v1beta1.Step) (map[string]v1beta1.Step, map[string][]string) {
+	stepMap := make(map[string]v1beta1.Step, len(steps))
+	parents := make(map[string][]string)
+
+	for _, step := range steps {
+		stepMap[step.Name] = step
+
+		for _, ref := range children(step) {
+			if !slices.Contains(parents[ref.Name], step.Name) {
+				parents[ref.Name] = append(parents[ref.Name], step.Name)
+			}
+		}
+	}
+
+	return stepMap, parents
+}
+
+// children returns the steps executed as part of the step.
+func children(step v1beta1.Step) []v1beta1.StepReference {
+	var refs []v1beta1.StepReference
+	for _, list := range stepRefs(step) {
+		if list.kind != EdgeNeeds {
+			refs = append(refs, list.refs...)
+		}
+	}
+
+	return refs
+}
+
+// dependencies returns the steps the step needs or references outputs from.
+func dependencies(step v1beta1.Step) []string {
+	var names []string
+	for _, ref := range step.Needs {
+		names = append(names, ref.Name)
+	}
+
+	walkStrings(step, "", func(_, value string) {
+		for _, match := range stepReference.FindAllStringSubmatch(value, -1) {
+			if name := match[1] + match[2]; !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	})
+
+	return names
+}
+
+// following returns the references which are executed after the given step.
+func following(refs []v1beta1.StepReference, name string) []v1beta1.StepReference {
+	i := slices.IndexFunc(refs, func(ref v1beta1.StepReference) bool {
+		return ref.Name == name
+	})
+
+	if i == -1 {
+		return nil
+	}
+
+	return refs[i+1:]
+}
+
+// preceding returns the references which are executed before the given step.
+func preceding(refs []v1beta1.StepReference, name string) []v1beta1.StepReference {
+	i := slices.IndexFunc(refs, func(ref v1beta1.StepReference) bool {
+		return ref.Name == name
+	})
+
+	if i == -1 {
+		return nil
+	}
+
+	return refs[:i]
+}
@@ -0,0 +1,210 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const selectManifest = `steps:
+- name: main
+  and:
+    refs:
+    - name: generate
+    - name: build
+    - name: tests
+    - name: publish
+- name: generate
+  run:
+    image: golang
+- name: build
+  run:
+    image: golang
+- name: tests
+  needs:
+  - name: lint
+  concurrent:
+    refs:
+    - name: test-api
+    - name: test-ui
+- name: lint
+  run:
+    image: golangci/golangci-lint
+- name: test-api
+  tags:
+  - name: team
+    value: api
+  run:
+    image: golang
+    args: ["$(context.steps.build.outputs.binary)"]
+- name: test-ui
+  tags:
+  - name: team
+    value: ui
+  run:
+    image: node
+- name: publish
+  pipe:
+    refs:
+    - name: package
+    - name: upload
+- name: package
+  run:
+    image: alpine
+- name: upload
+  run:
+    image: alpine
+- name: standalone
+  run:
+    image: alpine
+`
+
+func stepNames(pipeline v1beta1.Pipeline) []string {
+	var names []string
+	for _, step := range pipeline.Steps {
+		names = append(names, step.Name)
+	}
+
+	return names
+}
+
+func TestSelect(t *testing.T) {
+	tests := []struct {
+		name        string
+		targets     []string
+		done        []string
+		expectSteps []string
+		expectError string
+	}{
+		{
+			name:        "target with needs, and chain and output references",
+			targets:     []string{"test-api"},
+			expectSteps: []string{"main", "generate", "build", "tests", "lint", "test-api"},
+		},
+		{
+			name:        "done dependencies are not selected",
+			targets:     []string{"test-api"},
+			done:        []string{"generate", "build", "lint"},
+			expectSteps: []string{"main", "tests", "test-api"},
+		},
+		{
+			name:        "targets are executed entirely",
+			targets:     []string{"tests"},
+			expectSteps: []string{"main", "generate", "build", "tests", "lint", "test-api", "test-ui"},
+		},
+		{
+			name:        "piped steps are selected even if done",
+			targets:     []string{"upload"},
+			done:        []string{"generate", "build", "tests", "lint", "test-api", "test-ui", "package"},
+			expectSteps: []string{"main", "publish", "package", "upload"},
+		},
+		{
+			name:        "unknown target",
+			targets:     []string{"unknown"},
+			expectError: `unknown step "unknown"`,
+		},
+		{
+			name:        "target not reachable from entrypoint",
+			targets:     []string{"standalone"},
+			expectError: `step "standalone" is not reachable from entrypoint "main"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pipeline := decodeManifest(t, selectManifest)
+			done := func(name string) bool {
+				for _, step := range tt.done {
+					if step == name {
+						return true
+					}
+				}
+
+				return false
+			}
+
+			selected, err := Select(pipeline, "", tt.targets, done)
+			if tt.expectError != "" {
+				require.EqualError(t, err, tt.expectError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectSteps, stepNames(selected))
+			assert.Len(t, pipeline.Steps, 11, "the original pipeline must not be modified")
+		})
+	}
+}
+
+func TestSelect_PrunesReferences(t *testing.T) {
+	pipeline := decodeManifest(t, selectManifest)
+
+	selected, err := Select(pipeline, "", []string{"test-ui"}, func(name string) bool {
+		return name == "lint"
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"main", "generate", "build", "tests", "test-ui"}, stepNames(selected))
+	assert.Equal(t, []v1beta1.StepReference{{Name: "generate"}, {Name: "build"}, {Name: "tests"}}, selected.Steps[0].And.Refs)
+	assert.Empty(t, selected.Steps[3].Needs)
+	assert.Equal(t, []v1beta1.StepReference{{Name: "test-ui"}}, selected.Steps[3].Concurrent.Refs)
+}
+
+func TestSelect_Entrypoint(t *testing.T) {
+	selected, err := Select(decodeManifest(t, selectManifest), "standalone", []string{"standalone"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"standalone"}, stepNames(selected))
+}
+
+func TestStepsByTags(t *testing.T) {
+	pipeline := decodeManifest(t, selectManifest)
+
+	assert.Equal(t, []string{"test-api"}, StepsByTags(pipeline, []string{"team=api"}))
+	assert.Equal(t, []string{"test-api", "test-ui"}, StepsByTags(pipeline, []string{"team"}))
+	assert.Equal(t, []string{"test-ui"}, StepsByTags(pipeline, []string{"team=ui", "env=prod"}))
+	assert.Empty(t, StepsByTags(pipeline, []string{"team=web"}))
+}
+
+func TestInvalidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		steps       []string
+		expectSteps []string
+	}{
+		{
+			name:        "dependents and following steps of and chains",
+			steps:       []string{"build"},
+			expectSteps: []string{"main", "build", "tests", "test-api", "test-ui", "publish", "package", "upload"},
+		},
+		{
+			name:        "containing steps are invalidated without their other steps",
+			steps:       []string{"test-ui"},
+			expectSteps: []string{"main", "tests", "test-ui", "publish", "package", "upload"},
+		},
+		{
+			name:        "piped steps are invalidated together",
+			steps:       []string{"package"},
+			expectSteps: []string{"main", "publish", "package", "upload"},
+		},
+		{
+			name:        "needs",
+			steps:       []string{"lint"},
+			expectSteps: []string{"main", "tests", "lint", "test-api", "test-ui", "publish", "package", "upload"},
+		},
+		{
+			name:        "independent step",
+			steps:       []string{"standalone"},
+			expectSteps: []string{"standalone"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := Invalidate(decodeManifest(t, selectManifest), tt.steps)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectSteps, steps)
+		})
+	}
+}
@@ -0,0 +1,634 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/raffis/rageta/internal/substitute"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"gopkg.in/yaml.v3"
+)
+
+type Severity string
+
+var (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Problem is a single finding of the pipeline validation.
+// Line and Column are only set if the source manifest is available.
+type Problem struct {
+	Severity Severity `json:"severity"`
+	Path     string   `json:"path"`
+	Line     int      `json:"line,omitempty"`
+	Column   int      `json:"column,omitempty"`
+	Step     string   `json:"step,omitempty"`
+	Message  string   `json:"message"`
+}
+
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s: %s", p.Path, p.Severity, p.Message)
+	}
+
+	return fmt.Sprintf("%d:%d: %s: %s (%s)", p.Line, p.Column, p.Severity, p.Message, p.Path)
+}
+
+type position struct {
+	line   int
+	column int
+}
+
+type validator struct {
+	pipeline  v1beta1.Pipeline
+	celEnv    *cel.Env
+	positions map[string]position
+	steps     map[string]int
+	resolved  []v1beta1.Step
+	problems  []Problem
+}
+
+// Validate performs a static analysis of the pipeline and returns all problems found.
+// The manifest is optional and only used to map problems to their line in the yaml source.
+func Validate(pipeline v1beta1.Pipeline, manifest []byte, celEnv *cel.Env) []Problem {
+	v := &validator{
+		pipeline:  pipeline,
+		celEnv:    celEnv,
+		positions: indexPositions(manifest),
+		steps:     make(map[string]int),
+	}
+
+	v.validateSteps()
+	v.resolveExtends()
+	v.validateTypes()
+	v.validateRefs()
+	v.validateCycles()
+	v.validateExpressions()
+	v.validateImages()
+	v.validateLocks()
+
+	slices.SortStableFunc(v.problems, func(a, b Problem) int {
+		return a.Line - b.Line
+	})
+
+	return v.problems
+}
+
+func (v *validator) report(severity Severity, path, step, message string, args ...any) {
+	pos := v.lookup(path)
+	v.problems = append(v.problems, Problem{
+		Severity: severity,
+		Path:     path,
+		Line:     pos.line,
+		Column:   pos.column,
+		Step:     step,
+		Message:  fmt.Sprintf(message, args...),
+	})
+}
+
+// lookup returns the position of the path or the closest parent found in the manifest.
+func (v *validator) lookup(path string) position {
+	for path != "" {
+		if pos, ok := v.positions[path]; ok {
+			return pos
+		}
+
+		i := strings.LastIndexAny(path, ".[")
+		if i == -1 {
+			break
+		}
+
+		path = path[:i]
+	}
+
+	return position{}
+}
+
+func (v *validator) validateSteps() {
+	for i, step := range v.pipeline.Steps {
+		path := fmt.Sprintf("steps[%d]", i)
+
+		if step.Name == "" {
+			v.report(SeverityError, path, "", "step has no name")
+		} else if _, ok := v.steps[step.Name]; ok {
+			v.report(SeverityError, path+".name", step.Name, "duplicate step %q", step.Name)
+		} else {
+			v.steps[step.Name] = i
+		}
+	}
+}
+
+func (v *validator) validateTypes() {
+	for i, step := range v.resolved {
+		path := fmt.Sprintf("steps[%d]", i)
+		types := stepTypes(step)
+
+		switch len(types) {
+		case 0:
+			v.report(SeverityError, path, step.Name, "step has no step type, expected one of run, inherit, approval, and, pipe, concurrent, switch, forEach")
+		case 1:
+		default:
+			v.report(SeverityError, path, step.Name, "step has multiple step types: %s", strings.Join(types, ", "))
+		}
+
+		switch {
+		case step.Inherit == nil:
+		case step.Inherit.Pipeline == "" && step.Inherit.Generated == nil:
+			v.report(SeverityError, path+".inherit", step.Name, "inherit step has no pipeline reference")
+		case step.Inherit.Pipeline != "" && step.Inherit.Generated != nil:
+			v.report(SeverityError, path+".inherit", step.Name, "inherit step has both a pipeline reference and a generated pipeline")
+		}
+
+		if step.Switch != nil {
+			if len(step.Switch.Cases) == 0 && step.Switch.Default == nil {
+				v.report(SeverityError, path+".switch", step.Name, "switch step has neither cases nor a default")
+			}
+
+			for j, c := range step.Switch.Cases {
+				if c.CelExpression == "" {
+					v.report(SeverityError, fmt.Sprintf("%s.switch.cases[%d]", path, j), step.Name, "switch case has no celExpression")
+				}
+			}
+		}
+
+		if step.ForEach != nil && step.ForEach.Items == nil && step.ForEach.Until == "" && step.ForEach.While == "" {
+			v.report(SeverityError, path+".forEach", step.Name, "forEach step has neither items nor an until or while condition")
+		}
+
+		if step.Retry != nil && step.Retry.Constant.Duration == 0 && step.Retry.Exponential.Duration == 0 {
+			v.report(SeverityWarning, path+".retry", step.Name, "retry has neither a constant nor an exponential backoff and is ignored")
+		}
+	}
+}
+
+// resolveExtends merges steps with the steps they extend.
+// The remaining checks are done on the resolved steps as a step may inherit its type from another one.
+func (v *validator) resolveExtends() {
+	stepMap := make(map[string]v1beta1.Step, len(v.pipeline.Steps))
+	for _, step := range v.pipeline.Steps {
+		stepMap[step.Name] = step
+	}
+
+	v.resolved = slices.Clone(v.pipeline.Steps)
+	for i, step := range v.pipeline.Steps {
+		if step.Extends == nil {
+			continue
+		}
+
+		resolved, err := resolveStep(step.Name, stepMap, make(map[string]bool))
+		if err != nil {
+			// Unknown steps are reported by validateRefs
+			if _, ok := stepMap[step.Extends.Name]; ok {
+				v.report(SeverityError, fmt.Sprintf("steps[%d].extends", i), step.Name, "%s", err)
+			}
+
+			continue
+		}
+
+		v.resolved[i] = resolved
+	}
+}
+
+func stepTypes(step v1beta1.Step) []string {
+	var types []string
+	if step.Run != nil {
+		types = append(types, "run")
+	}
+	if step.Inherit != nil {
+		types = append(types, "inherit")
+	}
+	if step.Approval != nil {
+		types = append(types, "approval")
+	}
+	if step.And != nil {
+		types = append(types, "and")
+	}
+	if step.Pipe != nil {
+		types = append(types, "pipe")
+	}
+	if step.Concurrent != nil {
+		types = append(types, "concurrent")
+	}
+	if step.Switch != nil {
+		types = append(types, "switch")
+	}
+	if step.ForEach != nil {
+		types = append(types, "forEach")
+	}
+
+	return types
+}
+
+type stepRefList struct {
+	field string
+	kind  EdgeKind
+	refs  []v1beta1.StepReference
+}
+
+// stepRefs returns all step references of a step which may be executed for it (and, pipe, concurrent, switch, forEach, needs).
+func stepRefs(step v1beta1.Step) []stepRefList {
+	var refs []stepRefList
+	if step.And != nil {
+		refs = append(refs, stepRefList{field: "and.refs", kind: EdgeSequence, refs: step.And.Refs})
+	}
+	if step.Pipe != nil {
+		refs = append(refs, stepRefList{field: "pipe.refs", kind: EdgePipe, refs: step.Pipe.Refs})
+	}
+	if step.Concurrent != nil {
+		refs = append(refs, stepRefList{field: "concurrent.refs", kind: EdgeParallel, refs: step.Concurrent.Refs})
+	}
+	if step.Switch != nil {
+		for i, c := range step.Switch.Cases {
+			refs = append(refs, stepRefList{field: fmt.Sprintf("switch.cases[%d].refs", i), kind: EdgeCase, refs: c.Refs})
+		}
+		if step.Switch.Default != nil {
+			refs = append(refs, stepRefList{field: "switch.default.refs", kind: EdgeCase, refs: step.Switch.Default.Refs})
+		}
+	}
+	if step.ForEach != nil {
+		refs = append(refs, stepRefList{field: "forEach.refs", kind: EdgeLoop, refs: step.ForEach.Refs})
+	}
+	if len(step.Needs) > 0 {
+		refs = append(refs, stepRefList{field: "needs", kind: EdgeNeeds, refs: step.Needs})
+	}
+
+	return refs
+}
+
+func (v *validator) checkRef(path, step, ref string) {
+	if _, ok := v.steps[ref]; !ok {
+		v.report(SeverityError, path, step, "reference to unknown step %q", ref)
+	}
+}
+
+func (v *validator) validateRefs() {
+	if v.pipeline.Entrypoint != "" {
+		v.checkRef("entrypoint", "", v.pipeline.Entrypoint)
+	}
+
+	for i, step := range v.resolved {
+		path := fmt.Sprintf("steps[%d]", i)
+		for _, list := range stepRefs(step) {
+			for j, ref := range list.refs {
+				v.checkRef(fmt.Sprintf("%s.%s[%d].name", path, list.field, j), step.Name, ref.Name)
+			}
+		}
+
+		if extends := v.pipeline.Steps[i].Extends; extends != nil {
+			v.checkRef(path+".extends.name", step.Name, extends.Name)
+		}
+
+		for j, output := range step.Outputs {
+			if output.Step.Name != "" {
+				v.checkRef(fmt.Sprintf("%s.outputs[%d].step.name", path, j), step.Name, output.Step.Name)
+			}
+		}
+
+		if step.Inherit != nil && step.Inherit.Generated != nil {
+			v.checkGenerated(path+".inherit.generated", step.Name, *step.Inherit.Generated)
+		}
+	}
+
+	for i, output := range v.pipeline.Outputs {
+		v.checkRef(fmt.Sprintf("outputs[%d].step.name", i), "", output.Step.Name)
+	}
+}
+
+// checkGenerated verifies a generated pipeline references a pipeline output of an existing step.
+func (v *validator) checkGenerated(path, step string, generated v1beta1.GeneratedPipeline) {
+	i, ok := v.steps[generated.Step.Name]
+	if !ok {
+		v.report(SeverityError, path+".step.name", step, "reference to unknown step %q", generated.Step.Name)
+		return
+	}
+
+	outputs := v.resolved[i].Outputs
+	j := slices.IndexFunc(outputs, func(output v1beta1.StepOutputParam) bool {
+		return output.Name == generated.Output
+	})
+
+	switch {
+	case j == -1:
+		v.report(SeverityError, path+".output", step, "step %q has no output %q", generated.Step.Name, generated.Output)
+	case outputs[j].Type != v1beta1.StepOutputTypePipeline:
+		v.report(SeverityError, path+".output", step, "output %q of step %q is not of type pipeline", generated.Output, generated.Step.Name)
+	}
+}
+
+func (v *validator) validateCycles() {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := make(map[string]int)
+	reported := make(map[string]bool)
+	var stack []string
+
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		stack = append(stack, name)
+
+		step := v.resolved[v.steps[name]]
+		for _, list := range stepRefs(step) {
+			for _, ref := range list.refs {
+				if _, ok := v.steps[ref.Name]; !ok {
+					continue
+				}
+
+				switch state[ref.Name] {
+				case visiting:
+					cycle := append(slices.Clone(stack[slices.Index(stack, ref.Name):]), ref.Name)
+					key := strings.Join(slices.Sorted(slices.Values(cycle[:len(cycle)-1])), ",")
+					if !reported[key] {
+						reported[key] = true
+						v.report(SeverityError, fmt.Sprintf("steps[%d]", v.steps[ref.Name]), ref.Name, "dependency cycle: %s", strings.Join(cycle, " -> "))
+					}
+				case unvisited:
+					visit(ref.Name)
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+	}
+
+	for _, step := range v.pipeline.Steps {
+		if _, ok := v.steps[step.Name]; ok && state[step.Name] == unvisited {
+			visit(step.Name)
+		}
+	}
+}
+
+func (v *validator) compileCEL(path, step, expr string) {
+	if v.celEnv == nil {
+		return
+	}
+
+	if _, issues := v.celEnv.Compile(expr); issues != nil && issues.Err() != nil {
+		v.report(SeverityError, path, step, "invalid cel expression: %s", issues.Err())
+	}
+}
+
+func (v *validator) validateExpressions() {
+	inputs := make(map[string]bool)
+	for _, input := range v.pipeline.Inputs {
+		inputs[input.Name] = false
+	}
+
+	for i, input := range v.pipeline.Inputs {
+		if input.CelExpression != nil {
+			v.compileCEL(fmt.Sprintf("inputs[%d].celExpression", i), "", *input.CelExpression)
+		}
+	}
+
+	// Inputs declared by steps are valid within substitutions as well
+	declared := make(map[string]bool)
+	for _, step := range v.pipeline.Steps {
+		for _, input := range step.Inputs {
+			declared[input.Name] = true
+		}
+	}
+
+	for i, step := range v.pipeline.Steps {
+		path := fmt.Sprintf("steps[%d]", i)
+
+		if step.Switch != nil {
+			for j, c := range step.Switch.Cases {
+				if c.CelExpression != "" {
+					v.compileCEL(fmt.Sprintf("%s.switch.cases[%d].celExpression", path, j), step.Name, c.CelExpression)
+				}
+			}
+		}
+
+		if step.ForEach != nil {
+			if step.ForEach.Until != "" {
+				v.compileCEL(path+".forEach.until", step.Name, step.ForEach.Until)
+			}
+
+			if step.ForEach.While != "" {
+				v.compileCEL(path+".forEach.while", step.Name, step.ForEach.While)
+			}
+		}
+
+		if step.AllowFailure != nil && step.AllowFailure.CelExpression != "" {
+			v.compileCEL(path+".allowFailure.celExpression", step.Name, step.AllowFailure.CelExpression)
+		}
+
+		if step.Retry != nil && step.Retry.RetryOn != nil && step.Retry.RetryOn.CelExpression != "" {
+			v.compileCEL(path+".retry.retryOn.celExpression", step.Name, step.Retry.RetryOn.CelExpression)
+		}
+
+		for j, condition := range step.If {
+			if condition.CelExpression != nil {
+				v.compileCEL(fmt.Sprintf("%s.if[%d].celExpression", path, j), step.Name, *condition.CelExpression)
+			}
+		}
+
+		for j, input := range step.Inputs {
+			if input.CelExpression != nil {
+				v.compileCEL(fmt.Sprintf("%s.inputs[%d].celExpression", path, j), step.Name, *input.CelExpression)
+			}
+		}
+
+		walkStrings(step, path, func(path, value string) {
+			markUsedInputs(value, inputs)
+
+			for _, expr := range substitute.Expressions(value) {
+				parts := strings.Split(expr, ".")
+				if len(parts) < 3 || parts[0] != "context" {
+					continue
+				}
+
+				switch parts[1] {
+				case "inputs":
+					if _, ok := inputs[parts[2]]; !ok && !declared[parts[2]] {
+						v.report(SeverityError, path, step.Name, "substitution `$(%s)` references unknown input %q", expr, parts[2])
+					}
+				case "steps":
+					if _, ok := v.steps[parts[2]]; !ok {
+						v.report(SeverityError, path, step.Name, "substitution `$(%s)` references unknown step %q", expr, parts[2])
+					}
+				}
+			}
+		})
+	}
+
+	for i, input := range v.pipeline.Inputs {
+		if !inputs[input.Name] {
+			v.report(SeverityWarning, fmt.Sprintf("inputs[%d]", i), "", "input %q is not used", input.Name)
+		}
+	}
+}
+
+// markUsedInputs flags all inputs referenced by a substitution or cel expression within the value.
+func markUsedInputs(value string, inputs map[string]bool) {
+	if !strings.Contains(value, "inputs") {
+		return
+	}
+
+	for name := range inputs {
+		quoted := regexp.QuoteMeta(name)
+		if regexp.MustCompile(`inputs(\.` + quoted + `\b|\[["']` + quoted + `["']\])`).MatchString(value) {
+			inputs[name] = true
+		}
+	}
+}
+
+// walkStrings calls fn for every string value within the json representation of the step.
+func walkStrings(step v1beta1.Step, path string, fn func(path, value string)) {
+	b, err := json.Marshal(step)
+	if err != nil {
+		return
+	}
+
+	var doc any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return
+	}
+
+	var walk func(node any, path string)
+	walk = func(node any, path string) {
+		switch node := node.(type) {
+		case string:
+			fn(path, node)
+		case []any:
+			for i, child := range node {
+				walk(child, fmt.Sprintf("%s[%d]", path, i))
+			}
+		case map[string]any:
+			for _, key := range slices.Sorted(maps.Keys(node)) {
+				walk(node[key], path+"."+key)
+			}
+		}
+	}
+
+	walk(doc, path)
+}
+
+// validateImages reports run steps without an image which is neither inherited by the template
+// of any step referencing it.
+func (v *validator) validateImages() {
+	templated := make(map[string]bool)
+
+	var inherit func(name string)
+	inherit = func(name string) {
+		if templated[name] {
+			return
+		}
+
+		templated[name] = true
+		i, ok := v.steps[name]
+		if !ok {
+			return
+		}
+
+		for _, list := range stepRefs(v.resolved[i]) {
+			if list.field == "needs" {
+				continue
+			}
+
+			for _, ref := range list.refs {
+				inherit(ref.Name)
+			}
+		}
+	}
+
+	for _, step := range v.resolved {
+		if step.Template != nil && step.Template.Image != "" {
+			inherit(step.Name)
+		}
+	}
+
+	for i, step := range v.resolved {
+		if step.Run != nil && step.Run.Image == "" && !templated[step.Name] {
+			v.report(SeverityError, fmt.Sprintf("steps[%d].run", i), step.Name, "run step has no image")
+		}
+	}
+}
+
+// indexPositions maps the json path of every yaml node to its position in the manifest.
+func indexPositions(manifest []byte) map[string]position {
+	positions := make(map[string]position)
+	if len(manifest) == 0 {
+		return positions
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(manifest, &doc); err != nil {
+		return positions
+	}
+
+	var walk func(node *yaml.Node, path string)
+	walk = func(node *yaml.Node, path string) {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, child := range node.Content {
+				walk(child, path)
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i]
+				childPath := key.Value
+				if path != "" {
+					childPath = path + "." + key.Value
+				}
+
+				positions[childPath] = position{line: key.Line, column: key.Column}
+				walk(node.Content[i+1], childPath)
+			}
+		case yaml.SequenceNode:
+			for i, child := range node.Content {
+				childPath := fmt.Sprintf("%s[%d]", path, i)
+				positions[childPath] = position{line: child.Line, column: child.Column}
+				walk(child, childPath)
+			}
+		case yaml.AliasNode:
+			walk(node.Alias, path)
+		}
+	}
+
+	walk(&doc, "")
+	return positions
+}
+
+func (v *validator) validateLocks() {
+	capacities := make(map[string]int)
+
+	for _, step := range v.resolved {
+		if step.Lock != "" {
+			capacities[step.Lock] = 1
+		}
+	}
+
+	for i, step := range v.resolved {
+		for j, semaphore := range step.Semaphores {
+			path := fmt.Sprintf("steps[%d].semaphores[%d]", i, j)
+			capacity := max(semaphore.Capacity, 1)
+
+			switch {
+			case semaphore.Name == "":
+				v.report(SeverityError, path, step.Name, "semaphore has no name")
+				continue
+			case semaphore.Weight > capacity:
+				v.report(SeverityError, path+".weight", step.Name, "semaphore weight %d exceeds its capacity of %d", semaphore.Weight, capacity)
+			}
+
+			if existing, ok := capacities[semaphore.Name]; ok && existing != capacity {
+				v.report(SeverityWarning, path+".capacity", step.Name, "semaphore %q is used with different capacities %d and %d", semaphore.Name, existing, capacity)
+				continue
+			}
+
+			capacities[semaphore.Name] = capacity
+		}
+	}
+}
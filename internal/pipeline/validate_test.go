@@ -0,0 +1,248 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+const invalidManifest = `apiVersion: core.rageta.io/v1beta1
+kind: Pipeline
+metadata:
+  name: invalid
+entrypoint: main
+inputs:
+- name: version
+- name: unused
+steps:
+- name: main
+  and:
+    refs:
+    - name: build
+    - name: does-not-exist
+- name: build
+  needs:
+  - name: test
+  if:
+  - celExpression: "context.inputs.version =="
+  run:
+    image: golang
+    args:
+    - $(context.inputs.version)
+    - $(context.inputs.missing)
+    - $(context.steps.unknown.outputs.foo)
+    - $$(context.steps.escaped.outputs.foo)
+- name: test
+  needs:
+  - name: build
+  run:
+    script: go test
+- name: nothing
+- name: both
+  run:
+    image: alpine
+  inherit:
+    pipeline: ghcr.io/org/pipeline:v1
+- name: deploy
+  switch:
+    cases:
+    - celExpression: "context.inputs.version == "
+      refs:
+      - name: test
+    - refs:
+      - name: missing
+- name: empty
+  switch: {}
+- name: poll
+  forEach:
+    until: "context.loop.index =="
+    refs:
+    - name: test
+- name: endless
+  forEach:
+    refs:
+    - name: test
+- name: migrate
+  lock: db
+  semaphores:
+  - name: db
+    capacity: 2
+  - weight: 1
+  - name: docker
+    capacity: 2
+    weight: 3
+  approval: {}
+- name: flaky
+  retry:
+    maxRetries: 3
+    retryOn:
+      celExpression: "context.attempt.number <"
+  allowFailure:
+    celExpression: "context.attempt.exitCode =="
+  approval: {}
+`
+
+func decodeManifest(t *testing.T, manifest string) v1beta1.Pipeline {
+	t.Helper()
+
+	var pipeline v1beta1.Pipeline
+	require.NoError(t, yaml.Unmarshal([]byte(manifest), &pipeline))
+	return pipeline
+}
+
+func TestValidate(t *testing.T) {
+	celEnv, err := cel.NewEnv(cel.Variable("context", cel.DynType))
+	require.NoError(t, err)
+
+	problems := Validate(decodeManifest(t, invalidManifest), []byte(invalidManifest), celEnv)
+
+	type finding struct {
+		Severity Severity
+		Line     int
+		Step     string
+		Message  string
+	}
+
+	var findings []finding
+	for _, problem := range problems {
+		// The exact cel error depends on the cel-go version
+		message, _, _ := strings.Cut(problem.Message, ": ERROR")
+		findings = append(findings, finding{problem.Severity, problem.Line, problem.Step, message})
+	}
+
+	assert.ElementsMatch(t, []finding{
+		{SeverityWarning, 8, "", `input "unused" is not used`},
+		{SeverityError, 14, "main", `reference to unknown step "does-not-exist"`},
+		{SeverityError, 15, "build", "dependency cycle: build -> test -> build"},
+		{SeverityError, 19, "build", "invalid cel expression"},
+		{SeverityError, 24, "build", "substitution `$(context.inputs.missing)` references unknown input \"missing\""},
+		{SeverityError, 25, "build", "substitution `$(context.steps.unknown.outputs.foo)` references unknown step \"unknown\""},
+		{SeverityError, 30, "test", "run step has no image"},
+		{SeverityError, 32, "nothing", "step has no step type, expected one of run, inherit, approval, and, pipe, concurrent, switch, forEach"},
+		{SeverityError, 33, "both", "step has multiple step types: run, inherit"},
+		{SeverityError, 41, "deploy", "invalid cel expression"},
+		{SeverityError, 44, "deploy", "switch case has no celExpression"},
+		{SeverityError, 45, "deploy", `reference to unknown step "missing"`},
+		{SeverityError, 47, "empty", "switch step has neither cases nor a default"},
+		{SeverityError, 50, "poll", "invalid cel expression"},
+		{SeverityError, 54, "endless", "forEach step has neither items nor an until or while condition"},
+		{SeverityWarning, 61, "migrate", `semaphore "db" is used with different capacities 1 and 2`},
+		{SeverityError, 62, "migrate", "semaphore has no name"},
+		{SeverityError, 65, "migrate", "semaphore weight 3 exceeds its capacity of 2"},
+		{SeverityWarning, 68, "flaky", "retry has neither a constant nor an exponential backoff and is ignored"},
+		{SeverityError, 71, "flaky", "invalid cel expression"},
+		{SeverityError, 73, "flaky", "invalid cel expression"},
+	}, findings)
+}
+
+func TestValidate_Valid(t *testing.T) {
+	manifest := `apiVersion: core.rageta.io/v1beta1
+kind: Pipeline
+inputs:
+- name: version
+steps:
+- name: main
+  template:
+    image: golang
+  pipe:
+    refs:
+    - name: build
+    - name: test
+- name: base
+  run:
+    image: alpine
+- name: build
+  extends:
+    name: base
+  run:
+    script: echo $(context.inputs.version)
+- name: test
+  if:
+  - celExpression: context.inputs["version"] != ""
+  run:
+    script: echo $(context.steps.build.outputs.foo)
+`
+
+	celEnv, err := cel.NewEnv(cel.Variable("context", cel.DynType))
+	require.NoError(t, err)
+
+	problems := Validate(decodeManifest(t, manifest), []byte(manifest), celEnv)
+	assert.Empty(t, problems)
+}
+
+func TestValidate_WithoutManifest(t *testing.T) {
+	pipeline := v1beta1.Pipeline{
+		PipelineSpec: v1beta1.PipelineSpec{
+			Steps: []v1beta1.Step{
+				{Name: "a", And: &v1beta1.AndStep{Refs: []v1beta1.StepReference{{Name: "b"}}}},
+			},
+		},
+	}
+
+	problems := Validate(pipeline, nil, nil)
+	require.Len(t, problems, 1)
+	assert.Equal(t, Problem{
+		Severity: SeverityError,
+		Path:     "steps[0].and.refs[0].name",
+		Step:     "a",
+		Message:  `reference to unknown step "b"`,
+	}, problems[0])
+}
+
+func TestValidate_GeneratedPipeline(t *testing.T) {
+	manifest := `apiVersion: core.rageta.io/v1beta1
+kind: Pipeline
+steps:
+- name: main
+  and:
+    refs:
+    - name: generate
+    - name: services
+    - name: untyped
+    - name: unknown
+- name: generate
+  outputs:
+  - name: pipeline
+    type: pipeline
+  - name: value
+  run:
+    image: alpine
+- name: services
+  inherit:
+    generated:
+      step:
+        name: generate
+      output: pipeline
+- name: untyped
+  inherit:
+    generated:
+      step:
+        name: generate
+      output: value
+- name: unknown
+  inherit:
+    pipeline: ghcr.io/org/pipeline:v1
+    generated:
+      step:
+        name: missing
+      output: pipeline
+`
+
+	problems := Validate(decodeManifest(t, manifest), []byte(manifest), nil)
+
+	var messages []string
+	for _, problem := range problems {
+		messages = append(messages, problem.Step+": "+problem.Message)
+	}
+
+	assert.ElementsMatch(t, []string{
+		`untyped: output "value" of step "generate" is not of type pipeline`,
+		`unknown: inherit step has both a pipeline reference and a generated pipeline`,
+		`unknown: reference to unknown step "missing"`,
+	}, messages)
+}
@@ -1,36 +1,94 @@
 package processor
 
 import (
+	"errors"
 	"fmt"
 
+	"github.com/google/cel-go/cel"
 	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
 )
 
-func WithAllowFailure() ProcessorBuilder {
+func WithAllowFailure(celEnv *cel.Env) ProcessorBuilder {
 	return func(spec *v1beta1.Step) Bootstraper {
-		if !spec.AllowFailure {
+		if spec.AllowFailure == nil || (!spec.AllowFailure.Always && len(spec.AllowFailure.ExitCodes) == 0 && spec.AllowFailure.CelExpression == "") {
 			return nil
 		}
 
-		return &AllowFailure{}
+		return &AllowFailure{
+			stepName:     spec.Name,
+			celEnv:       celEnv,
+			allowFailure: *spec.AllowFailure,
+		}
 	}
 }
 
 type AllowFailure struct {
+	stepName     string
+	celEnv       *cel.Env
+	allowFailure v1beta1.AllowFailure
 }
 
 var ErrAllowFailure = &pipelineError{
 	message:      "ignore error returned from step",
-	result:       "allow-failure",
+	result:       "warning",
 	abortOnError: false,
 }
 
+// allowedFailure wraps an allowed failure as ErrAllowFailure while keeping a single error chain.
+type allowedFailure struct {
+	err error
+}
+
+func (e *allowedFailure) Error() string {
+	return fmt.Sprintf("%s: %s", ErrAllowFailure.Error(), e.err.Error())
+}
+
+func (e *allowedFailure) Unwrap() error {
+	return e.err
+}
+
+func (e *allowedFailure) Is(target error) bool {
+	return target == ErrAllowFailure
+}
+
+func (e *allowedFailure) AbortOnError() bool {
+	return ErrAllowFailure.AbortOnError()
+}
+
+func (e *allowedFailure) Result() string {
+	return ErrAllowFailure.Result()
+}
+
 func (s *AllowFailure) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
+	condition, err := newFailureCondition(s.celEnv, "allowFailure", s.allowFailure.ExitCodes, s.allowFailure.CelExpression)
+	if err != nil {
+		return nil, err
+	}
+
 	return func(ctx StepContext) (StepContext, error) {
 		ctx, err := next(ctx)
 
-		if err != nil {
-			err = fmt.Errorf("%w: %w", ErrAllowFailure, err)
+		// Skipped steps are not failures
+		if err == nil || !AbortOnError(err) {
+			return ctx, err
+		}
+
+		allowed := s.allowFailure.Always
+		if !allowed {
+			attempt, ok := ctx.AttemptOf(s.stepName)
+			if !ok {
+				attempt = 1
+			}
+
+			var evalErr error
+			allowed, evalErr = condition.matches(ctx, attempt, err)
+			if evalErr != nil {
+				return ctx, errors.Join(err, evalErr)
+			}
+		}
+
+		if allowed {
+			err = &allowedFailure{err: err}
 		}
 
 		return ctx, err
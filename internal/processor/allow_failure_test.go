@@ -1,9 +1,14 @@
 package processor
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"reflect"
 	"testing"
 
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
 	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -19,7 +24,7 @@ func TestAllowFailureBuilder(t *testing.T) {
 			name: "AllowFailure false returns nil",
 			spec: &v1beta1.Step{
 				StepOptions: v1beta1.StepOptions{
-					AllowFailure: false,
+					AllowFailure: &v1beta1.AllowFailure{},
 				},
 			},
 			expectNil: true,
@@ -28,7 +33,7 @@ func TestAllowFailureBuilder(t *testing.T) {
 			name: "AllowFailure true returns AllowFailure struct",
 			spec: &v1beta1.Step{
 				StepOptions: v1beta1.StepOptions{
-					AllowFailure: true,
+					AllowFailure: &v1beta1.AllowFailure{Always: true},
 				},
 			},
 			expectNil: false,
@@ -42,7 +47,7 @@ func TestAllowFailureBuilder(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			builder := WithAllowFailure()
+			builder := WithAllowFailure(nil)
 			bootstraper := builder(tt.spec)
 
 			if tt.expectNil {
@@ -84,7 +89,7 @@ func TestAllowFailureBootstrap(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			allowFailure := &AllowFailure{}
+			allowFailure := &AllowFailure{allowFailure: v1beta1.AllowFailure{Always: true}}
 			pipeline := &mockPipeline{}
 			nextCalled := false
 
@@ -115,3 +120,81 @@ func TestAllowFailureBootstrap(t *testing.T) {
 		})
 	}
 }
+
+func TestAllowFailureConditions(t *testing.T) {
+	celEnv, err := cel.NewEnv(
+		ext.NativeTypes(ext.ParseStructTags(true),
+			reflect.TypeOf(&v1beta1.Context{}),
+			reflect.TypeOf(&v1beta1.Attempt{}),
+		),
+		cel.Variable("context", cel.ObjectType("v1beta1.Context")),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		allowFailure string
+		inputError   error
+		allowed      bool
+	}{
+		{
+			name:         "bool allows every failure",
+			allowFailure: `true`,
+			inputError:   errors.New("test error"),
+			allowed:      true,
+		},
+		{
+			name:         "matching exit code is allowed",
+			allowFailure: `{"exitCodes": [1]}`,
+			inputError:   &ContainerError{exitCode: 1, err: errors.New("exit code 1")},
+			allowed:      true,
+		},
+		{
+			name:         "other exit codes fail",
+			allowFailure: `{"exitCodes": [1]}`,
+			inputError:   &ContainerError{exitCode: 2, err: errors.New("exit code 2")},
+		},
+		{
+			name:         "errors without exit code fail",
+			allowFailure: `{"exitCodes": [1]}`,
+			inputError:   errors.New("test error"),
+		},
+		{
+			name:         "cel expression over the failure",
+			allowFailure: `{"celExpression": "context.attempt.exitCode == 1 || context.attempt.error.contains('lint')"}`,
+			inputError:   errors.New("lint warnings found"),
+			allowed:      true,
+		},
+		{
+			name:         "cel expression not matching fails",
+			allowFailure: `{"celExpression": "context.attempt.exitCode == 1"}`,
+			inputError:   &ContainerError{exitCode: 2, err: errors.New("exit code 2")},
+		},
+		{
+			name:         "skipped steps are not wrapped",
+			allowFailure: `{"exitCodes": [1]}`,
+			inputError:   ErrConditionFalse,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var allowFailure v1beta1.AllowFailure
+			require.NoError(t, json.Unmarshal([]byte(tt.allowFailure), &allowFailure))
+
+			b, err := json.Marshal(allowFailure)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.allowFailure, string(b))
+
+			spec := &v1beta1.Step{StepOptions: v1beta1.StepOptions{AllowFailure: &allowFailure}}
+			nextFunc, err := WithAllowFailure(celEnv)(spec).Bootstrap(&mockPipeline{}, func(ctx StepContext) (StepContext, error) {
+				return ctx, tt.inputError
+			})
+			require.NoError(t, err)
+
+			_, err = nextFunc(StepContext{Context: context.Background()})
+			assert.ErrorIs(t, err, tt.inputError)
+			assert.Equal(t, tt.allowed, errors.Is(err, ErrAllowFailure))
+		})
+	}
+}
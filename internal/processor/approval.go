@@ -0,0 +1,137 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/raffis/rageta/internal/substitute"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+// Approver asks for a decision on an approval step, it blocks until a decision is made or the context is done.
+type Approver interface {
+	Approve(ctx context.Context, request ApprovalRequest) (ApprovalResult, error)
+}
+
+type ApprovalRequest struct {
+	StepName string
+	Message  string
+}
+
+type ApprovalResult struct {
+	Decision v1beta1.ApprovalDecision `json:"decision"`
+	Approver string                   `json:"approver,omitempty"`
+	TimedOut bool                     `json:"timedOut,omitempty"`
+}
+
+// String describes the decision, e.g. approved by alice.
+func (r ApprovalResult) String() string {
+	decision := "rejected"
+	if r.Decision == v1beta1.ApprovalDecisionApprove {
+		decision = "approved"
+	}
+
+	switch {
+	case r.TimedOut:
+		return decision + " after timeout"
+	case r.Approver != "":
+		return decision + " by " + r.Approver
+	default:
+		return decision
+	}
+}
+
+type ApprovalContext struct {
+	StepName string
+	Result   ApprovalResult
+}
+
+// ApprovalOf returns the approval decision if the context belongs to the given approval step.
+func (c StepContext) ApprovalOf(stepName string) (ApprovalResult, bool) {
+	if c.Approval.StepName == "" || c.Approval.StepName != stepName {
+		return ApprovalResult{}, false
+	}
+
+	return c.Approval.Result, true
+}
+
+func WithApproval(approver Approver) ProcessorBuilder {
+	return func(spec *v1beta1.Step) Bootstraper {
+		if spec.Approval == nil {
+			return nil
+		}
+
+		return &Approval{
+			stepName: spec.Name,
+			step:     *spec.Approval,
+			approver: approver,
+		}
+	}
+}
+
+var ErrApprovalRejected = errors.New("approval rejected")
+
+type Approval struct {
+	stepName string
+	step     v1beta1.ApprovalStep
+	approver Approver
+}
+
+func (s *Approval) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
+	return func(ctx StepContext) (StepContext, error) {
+		if s.approver == nil {
+			return ctx, errors.New("approval steps are not supported without an approver")
+		}
+
+		approval := s.step.DeepCopy()
+		if err := substitute.Substitute(ctx.ToV1Beta1(), &approval.Message); err != nil {
+			return ctx, err
+		}
+
+		var approveCtx context.Context
+		var cancel context.CancelFunc
+		if approval.Timeout.Duration > 0 {
+			approveCtx, cancel = context.WithTimeout(ctx.Context, approval.Timeout.Duration)
+		} else {
+			approveCtx, cancel = context.WithCancel(ctx.Context)
+		}
+
+		defer cancel()
+
+		result, err := s.approver.Approve(approveCtx, ApprovalRequest{
+			StepName: ctx.UniqueName(),
+			Message:  approval.Message,
+		})
+
+		switch {
+		case err != nil && errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil:
+			result = ApprovalResult{
+				Decision: approval.Default,
+				TimedOut: true,
+			}
+
+			if result.Decision == "" {
+				result.Decision = v1beta1.ApprovalDecisionReject
+			}
+		case err != nil:
+			return ctx, fmt.Errorf("approval failed: %w", err)
+		}
+
+		ctx.Approval = ApprovalContext{
+			StepName: s.stepName,
+			Result:   result,
+		}
+
+		switch {
+		case result.Decision == v1beta1.ApprovalDecisionApprove:
+			return next(ctx)
+		case result.TimedOut:
+			return ctx, fmt.Errorf("%w: no decision within %s", ErrApprovalRejected, approval.Timeout.Duration)
+		case result.Approver != "":
+			return ctx, fmt.Errorf("%w by %s", ErrApprovalRejected, result.Approver)
+		default:
+			return ctx, ErrApprovalRejected
+		}
+	}, nil
+}
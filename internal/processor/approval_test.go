@@ -0,0 +1,125 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type mockApprover struct {
+	result  ApprovalResult
+	err     error
+	request ApprovalRequest
+}
+
+func (m *mockApprover) Approve(ctx context.Context, request ApprovalRequest) (ApprovalResult, error) {
+	m.request = request
+	if m.err != nil {
+		return ApprovalResult{}, m.err
+	}
+
+	if m.result.Decision == "" {
+		<-ctx.Done()
+		return ApprovalResult{}, ctx.Err()
+	}
+
+	return m.result, nil
+}
+
+func TestApprovalBuilder(t *testing.T) {
+	assert.Nil(t, WithApproval(&mockApprover{})(&v1beta1.Step{}))
+	assert.NotNil(t, WithApproval(&mockApprover{})(&v1beta1.Step{
+		Approval: &v1beta1.ApprovalStep{},
+	}))
+}
+
+func TestApprovalBootstrap(t *testing.T) {
+	tests := []struct {
+		name         string
+		step         v1beta1.ApprovalStep
+		approver     *mockApprover
+		expectNext   bool
+		expectResult ApprovalResult
+		errorMsg     string
+	}{
+		{
+			name:         "approved",
+			step:         v1beta1.ApprovalStep{Message: "deploy $(context.inputs.env)?"},
+			approver:     &mockApprover{result: ApprovalResult{Decision: v1beta1.ApprovalDecisionApprove, Approver: "alice"}},
+			expectNext:   true,
+			expectResult: ApprovalResult{Decision: v1beta1.ApprovalDecisionApprove, Approver: "alice"},
+		},
+		{
+			name:         "rejected",
+			approver:     &mockApprover{result: ApprovalResult{Decision: v1beta1.ApprovalDecisionReject, Approver: "bob"}},
+			expectResult: ApprovalResult{Decision: v1beta1.ApprovalDecisionReject, Approver: "bob"},
+			errorMsg:     "approval rejected by bob",
+		},
+		{
+			name:         "timeout rejects by default",
+			step:         v1beta1.ApprovalStep{Timeout: metav1.Duration{Duration: 10 * time.Millisecond}},
+			approver:     &mockApprover{},
+			expectResult: ApprovalResult{Decision: v1beta1.ApprovalDecisionReject, TimedOut: true},
+			errorMsg:     "approval rejected: no decision within 10ms",
+		},
+		{
+			name:         "timeout with default outcome",
+			step:         v1beta1.ApprovalStep{Timeout: metav1.Duration{Duration: 10 * time.Millisecond}, Default: v1beta1.ApprovalDecisionApprove},
+			approver:     &mockApprover{},
+			expectNext:   true,
+			expectResult: ApprovalResult{Decision: v1beta1.ApprovalDecisionApprove, TimedOut: true},
+		},
+		{
+			name:     "approver error",
+			approver: &mockApprover{err: errors.New("no terminal")},
+			errorMsg: "approval failed: no terminal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &v1beta1.Step{
+				Name:     "confirm",
+				Approval: &tt.step,
+			}
+
+			var nextCalled bool
+			next, err := WithApproval(tt.approver)(spec).Bootstrap(&mockPipeline{}, func(ctx StepContext) (StepContext, error) {
+				nextCalled = true
+				return ctx, nil
+			})
+			require.NoError(t, err)
+
+			ctx := NewContext()
+			ctx.Context = context.Background()
+			ctx.InputVars.Inputs["env"] = *v1beta1.NewStructuredValues("prod")
+
+			ctx, err = next(ctx)
+			assert.Equal(t, tt.expectNext, nextCalled)
+
+			if tt.errorMsg != "" {
+				require.Error(t, err)
+				assert.Equal(t, tt.errorMsg, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tt.step.Message != "" {
+				assert.Equal(t, "deploy prod?", tt.approver.request.Message)
+			}
+
+			result, ok := ctx.ApprovalOf("confirm")
+			assert.Equal(t, tt.expectResult.Decision != "", ok)
+			assert.Equal(t, tt.expectResult, result)
+
+			_, ok = ctx.ApprovalOf("other")
+			assert.False(t, ok)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+type BreakpointPosition string
+
+const (
+	BreakpointBefore BreakpointPosition = "before"
+	BreakpointAfter  BreakpointPosition = "after"
+)
+
+type BreakpointAction string
+
+const (
+	BreakpointActionContinue BreakpointAction = "continue"
+	BreakpointActionStep     BreakpointAction = "step"
+	BreakpointActionAbort    BreakpointAction = "abort"
+)
+
+// Breakpointer pauses the pipeline at a breakpoint, it blocks until the execution is continued or the context is done.
+type Breakpointer interface {
+	Break(ctx context.Context, request BreakpointRequest) (BreakpointResult, error)
+	// Stepping returns true if the pipeline pauses before each step.
+	Stepping() bool
+}
+
+type BreakpointRequest struct {
+	StepName string
+	Position BreakpointPosition
+	Context  *v1beta1.Context
+}
+
+// BreakpointResult holds the chosen action and the inputs and envs changed for the remaining steps.
+type BreakpointResult struct {
+	Action BreakpointAction
+	Inputs map[string]v1beta1.ParamValue
+	Envs   map[string]string
+}
+
+func WithBreakpoint(breakpointer Breakpointer, before, after []string) ProcessorBuilder {
+	return func(spec *v1beta1.Step) Bootstraper {
+		if breakpointer == nil {
+			return nil
+		}
+
+		return &Breakpoint{
+			stepName:     spec.Name,
+			breakpointer: breakpointer,
+			before:       spec.Breakpoint || slices.Contains(before, spec.Name),
+			after:        slices.Contains(after, spec.Name),
+		}
+	}
+}
+
+var ErrBreakpointAbort = &pipelineError{
+	message:      "pipeline aborted at breakpoint",
+	result:       "aborted",
+	abortOnError: true,
+}
+
+type Breakpoint struct {
+	stepName     string
+	breakpointer Breakpointer
+	before       bool
+	after        bool
+}
+
+func (s *Breakpoint) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
+	return func(ctx StepContext) (StepContext, error) {
+		if s.before || s.breakpointer.Stepping() {
+			if err := s.pause(ctx, BreakpointBefore); err != nil {
+				return ctx, err
+			}
+		}
+
+		ctx, err := next(ctx)
+		if !s.after {
+			return ctx, err
+		}
+
+		if breakErr := s.pause(ctx, BreakpointAfter); breakErr != nil {
+			return ctx, breakErr
+		}
+
+		return ctx, err
+	}, nil
+}
+
+// pause waits at the breakpoint, changed inputs and envs apply to the remaining steps.
+func (s *Breakpoint) pause(ctx StepContext, position BreakpointPosition) error {
+	result, err := s.breakpointer.Break(ctx, BreakpointRequest{
+		StepName: s.stepName,
+		Position: position,
+		Context:  ctx.ToV1Beta1(),
+	})
+
+	if err != nil {
+		return fmt.Errorf("breakpoint failed: %w", err)
+	}
+
+	if result.Action == BreakpointActionAbort {
+		return ErrBreakpointAbort
+	}
+
+	maps.Copy(ctx.InputVars.Inputs, result.Inputs)
+	maps.Copy(ctx.EnvVars.Envs, result.Envs)
+	return nil
+}
@@ -0,0 +1,164 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBreakpointer struct {
+	results  []BreakpointResult
+	err      error
+	stepping bool
+	requests []BreakpointRequest
+}
+
+func (m *mockBreakpointer) Break(ctx context.Context, request BreakpointRequest) (BreakpointResult, error) {
+	m.requests = append(m.requests, request)
+	if m.err != nil {
+		return BreakpointResult{}, m.err
+	}
+
+	result := m.results[0]
+	m.results = m.results[1:]
+	return result, nil
+}
+
+func (m *mockBreakpointer) Stepping() bool {
+	return m.stepping
+}
+
+func TestBreakpointBuilder(t *testing.T) {
+	assert.Nil(t, WithBreakpoint(nil, []string{"build"}, nil)(&v1beta1.Step{Name: "build"}))
+	assert.NotNil(t, WithBreakpoint(&mockBreakpointer{}, nil, nil)(&v1beta1.Step{Name: "build"}))
+}
+
+func TestBreakpointBootstrap(t *testing.T) {
+	tests := []struct {
+		name           string
+		step           v1beta1.Step
+		before         []string
+		after          []string
+		breakpointer   *mockBreakpointer
+		nextErr        error
+		expectNext     bool
+		expectPosition []BreakpointPosition
+		expectInputs   map[string]v1beta1.ParamValue
+		expectEnvs     map[string]string
+		expectError    string
+	}{
+		{
+			name:         "no breakpoint",
+			step:         v1beta1.Step{Name: "build"},
+			breakpointer: &mockBreakpointer{},
+			expectNext:   true,
+		},
+		{
+			name:           "breakpoint field pauses before",
+			step:           v1beta1.Step{Name: "build", StepOptions: v1beta1.StepOptions{Breakpoint: true}},
+			breakpointer:   &mockBreakpointer{results: []BreakpointResult{{Action: BreakpointActionContinue}}},
+			expectNext:     true,
+			expectPosition: []BreakpointPosition{BreakpointBefore},
+		},
+		{
+			name:         "inputs and envs are changed",
+			step:         v1beta1.Step{Name: "build"},
+			before:       []string{"build"},
+			breakpointer: &mockBreakpointer{results: []BreakpointResult{{Action: BreakpointActionContinue, Inputs: map[string]v1beta1.ParamValue{"version": *v1beta1.NewStructuredValues("v2")}, Envs: map[string]string{"DEBUG": "1"}}}},
+			expectNext:   true,
+			expectInputs: map[string]v1beta1.ParamValue{
+				"version": *v1beta1.NewStructuredValues("v2"),
+			},
+			expectEnvs: map[string]string{
+				"DEBUG": "1",
+			},
+			expectPosition: []BreakpointPosition{BreakpointBefore},
+		},
+		{
+			name:           "pause before and after",
+			step:           v1beta1.Step{Name: "build"},
+			before:         []string{"build"},
+			after:          []string{"build"},
+			breakpointer:   &mockBreakpointer{results: []BreakpointResult{{Action: BreakpointActionStep}, {Action: BreakpointActionContinue}}},
+			expectNext:     true,
+			expectPosition: []BreakpointPosition{BreakpointBefore, BreakpointAfter},
+		},
+		{
+			name:           "stepping pauses before each step",
+			step:           v1beta1.Step{Name: "build"},
+			breakpointer:   &mockBreakpointer{stepping: true, results: []BreakpointResult{{Action: BreakpointActionContinue}}},
+			expectNext:     true,
+			expectPosition: []BreakpointPosition{BreakpointBefore},
+		},
+		{
+			name:           "pause after failed step",
+			step:           v1beta1.Step{Name: "build"},
+			after:          []string{"build"},
+			breakpointer:   &mockBreakpointer{results: []BreakpointResult{{Action: BreakpointActionContinue}}},
+			nextErr:        errors.New("exit code 1"),
+			expectNext:     true,
+			expectPosition: []BreakpointPosition{BreakpointAfter},
+			expectError:    "exit code 1",
+		},
+		{
+			name:           "abort before",
+			step:           v1beta1.Step{Name: "build"},
+			before:         []string{"build"},
+			breakpointer:   &mockBreakpointer{results: []BreakpointResult{{Action: BreakpointActionAbort}}},
+			expectPosition: []BreakpointPosition{BreakpointBefore},
+			expectError:    "pipeline aborted at breakpoint",
+		},
+		{
+			name:           "breakpointer error",
+			step:           v1beta1.Step{Name: "build"},
+			before:         []string{"build"},
+			breakpointer:   &mockBreakpointer{err: errors.New("no terminal")},
+			expectPosition: []BreakpointPosition{BreakpointBefore},
+			expectError:    "breakpoint failed: no terminal",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nextCalled bool
+			next, err := WithBreakpoint(tt.breakpointer, tt.before, tt.after)(&tt.step).Bootstrap(&mockPipeline{}, func(ctx StepContext) (StepContext, error) {
+				nextCalled = true
+				return ctx, tt.nextErr
+			})
+			require.NoError(t, err)
+
+			ctx := NewContext()
+			ctx.Context = context.Background()
+
+			ctx, err = next(ctx)
+			assert.Equal(t, tt.expectNext, nextCalled)
+
+			if tt.expectError != "" {
+				require.EqualError(t, err, tt.expectError)
+			} else {
+				require.NoError(t, err)
+			}
+
+			var positions []BreakpointPosition
+			for _, request := range tt.breakpointer.requests {
+				assert.Equal(t, "build", request.StepName)
+				assert.NotNil(t, request.Context)
+				positions = append(positions, request.Position)
+			}
+
+			assert.Equal(t, tt.expectPosition, positions)
+
+			if tt.expectInputs != nil {
+				assert.Equal(t, tt.expectInputs, ctx.InputVars.Inputs)
+			}
+
+			if tt.expectEnvs != nil {
+				assert.Equal(t, tt.expectEnvs, ctx.EnvVars.Envs)
+			}
+		})
+	}
+}
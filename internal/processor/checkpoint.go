@@ -0,0 +1,39 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+// Checkpointer persists the results of the steps known to the context once a step completed.
+type Checkpointer func(ctx StepContext) error
+
+func WithCheckpoint(checkpointer Checkpointer) ProcessorBuilder {
+	return func(spec *v1beta1.Step) Bootstraper {
+		if checkpointer == nil {
+			return nil
+		}
+
+		return &Checkpoint{
+			checkpointer: checkpointer,
+		}
+	}
+}
+
+type Checkpoint struct {
+	checkpointer Checkpointer
+}
+
+func (s *Checkpoint) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
+	return func(ctx StepContext) (StepContext, error) {
+		ctx, err := next(ctx)
+
+		if checkpointErr := s.checkpointer(ctx); checkpointErr != nil {
+			return ctx, errors.Join(err, fmt.Errorf("failed to checkpoint step context: %w", checkpointErr))
+		}
+
+		return ctx, err
+	}, nil
+}
@@ -0,0 +1,68 @@
+package processor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointBuilder(t *testing.T) {
+	assert.Nil(t, WithCheckpoint(nil)(&v1beta1.Step{}))
+	assert.NotNil(t, WithCheckpoint(func(ctx StepContext) error { return nil })(&v1beta1.Step{}))
+}
+
+func TestCheckpointBootstrap(t *testing.T) {
+	tests := []struct {
+		name          string
+		nextErr       error
+		checkpointErr error
+		expectError   string
+	}{
+		{
+			name: "step succeeds",
+		},
+		{
+			name:        "failed steps are checkpointed",
+			nextErr:     errors.New("step failed"),
+			expectError: "step failed",
+		},
+		{
+			name:          "checkpoint fails",
+			checkpointErr: errors.New("disk full"),
+			expectError:   "failed to checkpoint step context: disk full",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var checkpointed []string
+			checkpoint := &Checkpoint{
+				checkpointer: func(ctx StepContext) error {
+					for name := range ctx.Steps {
+						checkpointed = append(checkpointed, name)
+					}
+
+					return tt.checkpointErr
+				},
+			}
+
+			nextFunc, err := checkpoint.Bootstrap(&mockPipeline{}, func(ctx StepContext) (StepContext, error) {
+				ctx.Steps["test-step"] = &StepContext{}
+				return ctx, tt.nextErr
+			})
+			require.NoError(t, err)
+
+			_, err = nextFunc(NewContext())
+			assert.Equal(t, []string{"test-step"}, checkpointed)
+
+			if tt.expectError != "" {
+				require.EqualError(t, err, tt.expectError)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ type StepContext struct {
 	uniqueID        string
 	uniqueName      string
 	namespace       string
+	recovered       bool
 	Error           error
 	StartedAt       time.Time
 	EndedAt         time.Time
@@ -39,6 +40,11 @@ type StepContext struct {
 	Template        TemplateContext
 	Matrix          MatrixContext
 	Events          EventsContext
+	Approval        ApprovalContext
+	Switch          SwitchContext
+	Loop            LoopContext
+	Locks           LocksContext
+	Retry           RetryContext
 }
 
 func (c StepContext) UniqueID() string {
@@ -95,6 +101,9 @@ func (c StepContext) DeepCopy() StepContext {
 	copy.SecretVars.Secrets = maps.Clone(c.SecretVars.Secrets)
 	copy.Containers = maps.Clone(c.Containers)
 	copy.Matrix.Params = maps.Clone(c.Matrix.Params)
+	copy.Loop = c.Loop
+	copy.Locks = c.Locks
+	copy.Retry = c.Retry
 	if c.Template.Template != nil {
 		copy.Template.Template = c.Template.Template.DeepCopy()
 	}
@@ -127,11 +136,39 @@ func (t StepContext) FromV1Beta1(vars *v1beta1.Context) {
 		}
 	}
 
-	/*for k, v := range vars.Steps {
-		t.Steps[k] = &StepResult{
-			DataDir: v.TmpDir,
+	// Only successfully completed steps are recovered, their data dir stays within the previous context dir
+	for k, v := range vars.Steps {
+		if v.Error != "" || v.EndedAt.IsZero() {
+			continue
 		}
-	}*/
+
+		t.Steps[k] = &StepContext{
+			recovered:  true,
+			uniqueID:   path.Base(path.Dir(v.TmpDir)),
+			ContextDir: path.Dir(path.Dir(v.TmpDir)),
+			StartedAt:  v.StartedAt,
+			EndedAt:    v.EndedAt,
+			OutputVars: OutputVarsContext{
+				OutputVars: maps.Clone(v.Outputs),
+			},
+			EnvVars: EnvVarsContext{
+				Envs: maps.Clone(v.Envs),
+			},
+			SecretVars: SecretVarsContext{
+				Secrets: maps.Clone(v.Secrets),
+			},
+		}
+	}
+}
+
+// Recover adds the results of steps from a previous execution.
+// Only recovered steps are skipped as done, results of steps executed by the current execution are not.
+func (t StepContext) Recover(steps map[string]*StepContext) {
+	for k, v := range steps {
+		result := *v
+		result.recovered = true
+		t.Steps[k] = &result
+	}
 }
 
 func (t StepContext) ToV1Beta1() *v1beta1.Context {
@@ -152,6 +189,25 @@ func (t StepContext) ToV1Beta1() *v1beta1.Context {
 		Guid:       fmt.Sprintf("%d", os.Getgid()),
 	}
 
+	if t.Context != nil {
+		if deadline, ok := t.Deadline(); ok {
+			vars.Deadline = time.Until(deadline)
+		}
+	}
+
+	if t.Retry.Attempt > 0 {
+		vars.Attempt = &v1beta1.Attempt{
+			Number: t.Retry.Attempt,
+		}
+	}
+
+	if t.Loop.active {
+		vars.Loop = &v1beta1.Loop{
+			Item:  t.Loop.Item,
+			Index: t.Loop.Index,
+		}
+	}
+
 	for k, v := range t.Containers {
 		vars.Containers[k] = &v1beta1.ContainerStatus{
 			ContainerID: v.ContainerID,
@@ -166,6 +222,8 @@ func (t StepContext) ToV1Beta1() *v1beta1.Context {
 	for k, v := range t.Steps {
 		vars.Steps[k] = &v1beta1.StepResult{
 			Outputs:   make(map[string]v1beta1.ParamValue),
+			Envs:      maps.Clone(v.EnvVars.Envs),
+			Secrets:   maps.Clone(v.SecretVars.Secrets),
 			TmpDir:    path.Join(v.ContextDir, v.UniqueID(), "data"),
 			StartedAt: v.StartedAt,
 			EndedAt:   v.EndedAt,
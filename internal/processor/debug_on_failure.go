@@ -0,0 +1,32 @@
+package processor
+
+import (
+	"context"
+
+	"github.com/raffis/rageta/internal/runtime"
+)
+
+type DebugAction string
+
+const (
+	DebugActionRetry DebugAction = "retry"
+	DebugActionSkip  DebugAction = "skip"
+	DebugActionAbort DebugAction = "abort"
+)
+
+// Debugger is called once a run step failed, the failed container is kept until it returns.
+type Debugger interface {
+	Debug(ctx context.Context, request DebugRequest) (DebugAction, error)
+}
+
+type DebugRequest struct {
+	StepName string
+	Pod      *runtime.Pod
+	Error    error
+}
+
+var ErrSkipDebug = &pipelineError{
+	message:      "step skipped after debugging",
+	result:       "skipped-debug",
+	abortOnError: false,
+}
@@ -0,0 +1,82 @@
+package processor
+
+import (
+	"errors"
+	"maps"
+
+	"github.com/raffis/rageta/internal/dryrun"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+func WithDryRun(plan *dryrun.Plan) ProcessorBuilder {
+	return func(spec *v1beta1.Step) Bootstraper {
+		if plan == nil {
+			return nil
+		}
+
+		return &DryRun{
+			plan:          plan,
+			stepName:      spec.Name,
+			stepType:      stepType(spec),
+			hasConditions: len(spec.If) > 0 || len(spec.Sources) > 0,
+		}
+	}
+}
+
+type DryRun struct {
+	plan          *dryrun.Plan
+	stepName      string
+	stepType      string
+	hasConditions bool
+}
+
+func (s *DryRun) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
+	return func(ctx StepContext) (StepContext, error) {
+		step := &dryrun.Step{
+			Name:   ctx.UniqueName(),
+			Type:   s.stepType,
+			Matrix: maps.Clone(ctx.Matrix.Params),
+		}
+
+		s.plan.Add(step)
+		originContext := ctx.Context
+		ctx.Context = dryrun.WithStep(ctx.Context, step)
+
+		ctx, err := next(ctx)
+		ctx.Context = originContext
+
+		if s.hasConditions {
+			result := !errors.Is(err, ErrConditionFalse) && !errors.Is(err, ErrUnchanged)
+			step.If = &result
+		}
+
+		if err != nil && !AbortOnError(err) {
+			step.Skipped = ErrorResult(err)
+		}
+
+		return ctx, err
+	}, nil
+}
+
+func stepType(spec *v1beta1.Step) string {
+	switch {
+	case spec.Run != nil:
+		return "run"
+	case spec.Inherit != nil:
+		return "inherit"
+	case spec.Approval != nil:
+		return "approval"
+	case spec.And != nil:
+		return "and"
+	case spec.Pipe != nil:
+		return "pipe"
+	case spec.Concurrent != nil:
+		return "concurrent"
+	case spec.Switch != nil:
+		return "switch"
+	case spec.ForEach != nil:
+		return "forEach"
+	}
+
+	return ""
+}
@@ -0,0 +1,107 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/raffis/rageta/internal/dryrun"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunBuilder(t *testing.T) {
+	spec := &v1beta1.Step{
+		Name: "test-step",
+		StepOptions: v1beta1.StepOptions{
+			If: []v1beta1.IfCondition{
+				{CelExpression: stringPtr("true")},
+			},
+		},
+		Run: &v1beta1.RunStep{},
+	}
+
+	assert.Nil(t, WithDryRun(nil)(spec))
+
+	bootstraper := WithDryRun(dryrun.NewPlan())(spec)
+	dryRun, ok := bootstraper.(*DryRun)
+	require.True(t, ok)
+	assert.Equal(t, "test-step", dryRun.stepName)
+	assert.Equal(t, "run", dryRun.stepType)
+	assert.True(t, dryRun.hasConditions)
+}
+
+func TestDryRunBootstrap(t *testing.T) {
+	tests := []struct {
+		name          string
+		hasConditions bool
+		err           error
+		expectIf      *bool
+		expectSkipped string
+	}{
+		{
+			name: "step executed",
+		},
+		{
+			name:          "condition true",
+			hasConditions: true,
+			expectIf:      boolPtr(true),
+		},
+		{
+			name:          "condition false",
+			hasConditions: true,
+			err:           ErrConditionFalse,
+			expectIf:      boolPtr(false),
+			expectSkipped: "skipped-condition",
+		},
+		{
+			name: "step failed",
+			err:  errors.New("failed"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := dryrun.NewPlan()
+			dryRun := &DryRun{
+				plan:          plan,
+				stepName:      "test-step",
+				stepType:      "run",
+				hasConditions: tt.hasConditions,
+			}
+
+			next, err := dryRun.Bootstrap(&mockPipeline{}, func(ctx StepContext) (StepContext, error) {
+				step, ok := dryrun.StepFromContext(ctx)
+				require.True(t, ok)
+				step.AddContainer(dryrun.Container{Image: "alpine"})
+				return ctx, tt.err
+			})
+			require.NoError(t, err)
+
+			ctx := NewContext()
+			ctx.Context = context.Background()
+			ctx.uniqueName = "test-step"
+			ctx.Matrix.Params = map[string]string{"os": "linux"}
+
+			ctx, err = next(ctx)
+			assert.Equal(t, tt.err, err)
+
+			_, ok := dryrun.StepFromContext(ctx)
+			assert.False(t, ok, "step must not leak into the parent context")
+
+			require.Len(t, plan.Steps, 1)
+			step := plan.Steps[0]
+			assert.Equal(t, "test-step", step.Name)
+			assert.Equal(t, "run", step.Type)
+			assert.Equal(t, map[string]string{"os": "linux"}, step.Matrix)
+			assert.Equal(t, tt.expectIf, step.If)
+			assert.Equal(t, tt.expectSkipped, step.Skipped)
+			assert.Equal(t, []dryrun.Container{{Image: "alpine"}}, step.Containers)
+		})
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -92,8 +92,18 @@ func (s *Events) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 			_, _ = fmt.Fprintf(ctx.Events.Dev, "Task %q failed and pipeline is continued [%s]\n", ctx.UniqueName(), duration)
 		case errors.Is(err, ErrConditionFalse):
 			_, _ = fmt.Fprintf(ctx.Events.Dev, "Task %q condition check did not pass [%s]\n", ctx.UniqueName(), duration)
+		case errors.Is(err, ErrUnchanged):
+			_, _ = fmt.Fprintf(ctx.Events.Dev, "Task %q skipped as no files changed [%s]\n", ctx.UniqueName(), duration)
+		case errors.Is(err, ErrSwitchCaseSkipped):
+			_, _ = fmt.Fprintf(ctx.Events.Dev, "Task %q skipped as its switch case was not selected [%s]\n", ctx.UniqueName(), duration)
 		case errors.Is(err, ErrSkipDone):
 			_, _ = fmt.Fprintf(ctx.Events.Dev, "Task %q skipped as it was marked as done [%s]\n", ctx.UniqueName(), duration)
+		case errors.Is(err, ErrSkipBlacklist):
+			_, _ = fmt.Fprintf(ctx.Events.Dev, "Task %q skipped as it was excluded [%s]\n", ctx.UniqueName(), duration)
+		case errors.Is(err, ErrSkipDebug):
+			_, _ = fmt.Fprintf(ctx.Events.Dev, "Task %q failed and was skipped after debugging [%s]\n", ctx.UniqueName(), duration)
+		case errors.Is(err, ErrTimeout):
+			_, _ = fmt.Fprintf(ctx.Events.Dev, "Task %q timed out: %q [%s]\n", ctx.UniqueName(), err.Error(), duration)
 		default:
 			_, _ = fmt.Fprintf(ctx.Events.Dev, "Task %q failed: %q [%s]\n", ctx.UniqueName(), err.Error(), duration)
 		}
@@ -0,0 +1,87 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/cel-go/cel"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+// failureCondition matches a failed attempt by the exit code of the step container or a cel expression.
+// The failed attempt is available as context.attempt within the expression.
+type failureCondition struct {
+	name       string
+	exitCodes  []int
+	expression string
+	program    cel.Program
+}
+
+func newFailureCondition(celEnv *cel.Env, name string, exitCodes []int, expression string) (*failureCondition, error) {
+	condition := &failureCondition{
+		name:       name,
+		exitCodes:  exitCodes,
+		expression: expression,
+	}
+
+	if expression == "" {
+		return condition, nil
+	}
+
+	ast, issues := celEnv.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("%s expression compilation `%s` failed: %w", name, expression, issues.Err())
+	}
+
+	prg, err := celEnv.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("%s expression ast `%s` failed: %w", name, expression, err)
+	}
+
+	condition.program = prg
+	return condition, nil
+}
+
+func (c *failureCondition) empty() bool {
+	return len(c.exitCodes) == 0 && c.program == nil
+}
+
+// matches reports whether the failed attempt matches any of the exit codes or the expression.
+func (c *failureCondition) matches(ctx StepContext, attempt int, err error) (bool, error) {
+	exitCode := 0
+	var exitCodeErr ExitCode
+	if errors.As(err, &exitCodeErr) {
+		exitCode = exitCodeErr.ExitCode()
+
+		if slices.Contains(c.exitCodes, exitCode) {
+			return true, nil
+		}
+	}
+
+	if c.program == nil {
+		return false, nil
+	}
+
+	vars := ctx.ToV1Beta1()
+	vars.Attempt = &v1beta1.Attempt{
+		Number:   attempt,
+		Error:    err.Error(),
+		ExitCode: exitCode,
+	}
+
+	val, _, evalErr := c.program.Eval(map[string]any{
+		"context": vars,
+	})
+
+	if evalErr != nil {
+		return false, fmt.Errorf("%s expression `%s` failed: %w", c.name, c.expression, evalErr)
+	}
+
+	matches, ok := val.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("%s expression `%s` must evaluate to a bool", c.name, c.expression)
+	}
+
+	return matches, nil
+}
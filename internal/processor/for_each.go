@@ -0,0 +1,258 @@
+package processor
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/raffis/rageta/internal/dryrun"
+	"github.com/raffis/rageta/internal/substitute"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+const defaultMaxIterations = 100
+
+type LoopContext struct {
+	Item   string
+	Index  int
+	active bool
+}
+
+func WithForEach(celEnv *cel.Env) ProcessorBuilder {
+	return func(spec *v1beta1.Step) Bootstraper {
+		if spec.ForEach == nil {
+			return nil
+		}
+
+		maxIterations := spec.ForEach.MaxIterations
+		if maxIterations <= 0 {
+			maxIterations = defaultMaxIterations
+		}
+
+		return &ForEach{
+			stepName:      spec.Name,
+			celEnv:        celEnv,
+			step:          *spec.ForEach,
+			maxIterations: maxIterations,
+		}
+	}
+}
+
+type ForEach struct {
+	stepName      string
+	celEnv        *cel.Env
+	step          v1beta1.ForEachStep
+	maxIterations int
+}
+
+var ErrLoopMaxIterations = &pipelineError{
+	message:      "loop exceeded max iterations",
+	result:       "max-iterations",
+	abortOnError: true,
+}
+
+func (s *ForEach) compile(expr string) (cel.Program, error) {
+	if expr == "" {
+		return nil, nil
+	}
+
+	ast, issues := s.celEnv.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("loop expression compilation `%s` failed: %w", expr, issues.Err())
+	}
+
+	prg, err := s.celEnv.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("loop expression ast `%s` failed: %w", expr, err)
+	}
+
+	return prg, nil
+}
+
+func (s *ForEach) eval(ctx StepContext, prg cel.Program, expr string) (bool, error) {
+	value, _, err := prg.ContextEval(ctx, map[string]any{
+		"context": ctx.ToV1Beta1(),
+	})
+
+	if err != nil {
+		return false, fmt.Errorf("loop expression evaluation `%s` failed: %w", expr, err)
+	}
+
+	result, ok := value.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("loop expression `%s` must evaluate to a bool", expr)
+	}
+
+	return result, nil
+}
+
+// items returns the substituted loop items, nil if the loop has no items.
+func (s *ForEach) items(ctx StepContext) ([]string, error) {
+	if s.step.Items == nil {
+		return nil, nil
+	}
+
+	param := v1beta1.Param{
+		Name:  "items",
+		Value: *s.step.Items.DeepCopy(),
+	}
+
+	if err := substitute.Substitute(ctx.ToV1Beta1(), &param); err != nil {
+		return nil, fmt.Errorf("substitution failed for loop items: %w", err)
+	}
+
+	switch param.Value.Type {
+	case v1beta1.ParamTypeArray:
+		return param.Value.ArrayVal, nil
+	case v1beta1.ParamTypeString:
+		return []string{param.Value.StringVal}, nil
+	default:
+		return nil, fmt.Errorf("loop items must be an array but got %s", param.Value.Type)
+	}
+}
+
+func (s *ForEach) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
+	steps, err := filterSteps(refSlice(s.step.Refs), pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	until, err := s.compile(s.step.Until)
+	if err != nil {
+		return nil, err
+	}
+
+	while, err := s.compile(s.step.While)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx StepContext) (StepContext, error) {
+		items, err := s.items(ctx)
+		if err != nil {
+			return ctx, err
+		}
+
+		_, dryRun := dryrun.StepFromContext(ctx)
+
+		iterations := s.maxIterations
+		switch {
+		case items != nil && len(items) > s.maxIterations:
+			return ctx, fmt.Errorf("%w: %d items exceed the max iterations of %d", ErrLoopMaxIterations, len(items), s.maxIterations)
+		case items != nil:
+			iterations = len(items)
+		case dryRun:
+			// Polling loops only pass once in a dry run as the condition is never met without executing the refs
+			iterations = 1
+		}
+
+		outputs := make(map[string]v1beta1.ParamValue)
+		stopped := items != nil || dryRun
+
+		for i := range iterations {
+			if i > 0 && s.step.Interval.Duration > 0 && !dryRun {
+				select {
+				case <-ctx.Done():
+					return ctx, ctx.Err()
+				case <-time.After(s.step.Interval.Duration):
+				}
+			}
+
+			iterCtx := s.iteration(ctx, i, items)
+
+			if while != nil {
+				ok, err := s.eval(iterCtx, while, s.step.While)
+				if err != nil {
+					return ctx, err
+				}
+
+				if !ok {
+					stopped = true
+					break
+				}
+			}
+
+			_, _ = fmt.Fprintf(ctx.Events.Dev, "Task %q iteration %d with item %q\n", ctx.UniqueName(), i, iterCtx.Loop.Item)
+
+			for _, step := range steps {
+				entrypoint, err := step.Entrypoint()
+				if err != nil {
+					return ctx, err
+				}
+
+				iterCtx, err = entrypoint(iterCtx)
+				maps.Copy(ctx.Steps, iterCtx.Steps)
+
+				if AbortOnError(err) {
+					return ctx, err
+				}
+			}
+
+			// Unify the outputs of each iteration into an array output for the current step
+			for name, value := range iterCtx.OutputVars.OutputVars {
+				param, ok := outputs[name]
+				if !ok {
+					param = v1beta1.ParamValue{
+						Type: v1beta1.ParamTypeArray,
+					}
+				}
+
+				if value.Type == v1beta1.ParamTypeString {
+					param.ArrayVal = append(param.ArrayVal, value.StringVal)
+				}
+
+				outputs[name] = param
+			}
+
+			if until != nil {
+				ok, err := s.eval(iterCtx, until, s.step.Until)
+				if err != nil {
+					return ctx, err
+				}
+
+				if ok {
+					stopped = true
+					break
+				}
+			}
+		}
+
+		maps.Copy(ctx.OutputVars.OutputVars, outputs)
+
+		if !stopped {
+			return ctx, fmt.Errorf("%w: condition not met within %d iterations", ErrLoopMaxIterations, s.maxIterations)
+		}
+
+		return next(ctx)
+	}, nil
+}
+
+// iteration creates the context of a single iteration, the refs of each iteration are namespaced and tagged by the item.
+func (s *ForEach) iteration(ctx StepContext, index int, items []string) StepContext {
+	loop := LoopContext{
+		Index:  index,
+		active: true,
+	}
+
+	tag := Tag{
+		Key:   fmt.Sprintf("loop/%s", s.stepName),
+		Value: fmt.Sprintf("#%d", index),
+	}
+
+	if items != nil {
+		loop.Item = items[index]
+		tag.Value = loop.Item
+	}
+
+	hasher := sha1.New()
+	hasher.Write(fmt.Appendf(nil, "%s-%d", s.stepName, index))
+	b := hasher.Sum(nil)
+
+	iterCtx := ctx.DeepCopy().WithNamespace(fmt.Sprintf("%x", b)[:6])
+	iterCtx.OutputVars.OutputVars = make(map[string]v1beta1.ParamValue)
+	iterCtx.Loop = loop
+	iterCtx.Tags.Add(tag)
+	return iterCtx
+}
@@ -0,0 +1,185 @@
+package processor
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEach(t *testing.T) {
+	celEnv, err := cel.NewEnv(
+		ext.NativeTypes(ext.ParseStructTags(true),
+			reflect.TypeOf(&v1beta1.Context{}),
+			reflect.TypeOf(&v1beta1.StepResult{}),
+			reflect.TypeOf(&v1beta1.ParamValue{}),
+			reflect.TypeOf(&v1beta1.Loop{}),
+		),
+		cel.Variable("context", cel.ObjectType("v1beta1.Context")),
+	)
+	require.NoError(t, err)
+
+	items := v1beta1.NewStructuredValues("eu", "us", "ap")
+
+	tests := []struct {
+		name     string
+		step     v1beta1.ForEachStep
+		inputs   map[string]v1beta1.ParamValue
+		items    []string
+		indexes  []int
+		outputs  []string
+		errorMsg string
+	}{
+		{
+			name:    "iterates all items",
+			step:    v1beta1.ForEachStep{Items: items},
+			items:   []string{"eu", "us", "ap"},
+			indexes: []int{0, 1, 2},
+			outputs: []string{"region-eu", "region-us", "region-ap"},
+		},
+		{
+			name: "items from an input",
+			step: v1beta1.ForEachStep{Items: v1beta1.NewStructuredValues("$(context.inputs.regions)")},
+			inputs: map[string]v1beta1.ParamValue{
+				"regions": *v1beta1.NewStructuredValues("eu", "us"),
+			},
+			items:   []string{"eu", "us"},
+			indexes: []int{0, 1},
+			outputs: []string{"region-eu", "region-us"},
+		},
+		{
+			name:    "until stops the loop",
+			step:    v1beta1.ForEachStep{Items: items, Until: `context.loop.item == "us"`},
+			items:   []string{"eu", "us"},
+			indexes: []int{0, 1},
+			outputs: []string{"region-eu", "region-us"},
+		},
+		{
+			name:    "while stops the loop",
+			step:    v1beta1.ForEachStep{Items: items, While: `context.loop.index < 1`},
+			items:   []string{"eu"},
+			indexes: []int{0},
+			outputs: []string{"region-eu"},
+		},
+		{
+			name:    "polling loop without items",
+			step:    v1beta1.ForEachStep{Until: `context.loop.index == 3`},
+			items:   []string{"", "", "", ""},
+			indexes: []int{0, 1, 2, 3},
+			outputs: []string{"region-", "region-", "region-", "region-"},
+		},
+		{
+			name:     "polling loop exceeds max iterations",
+			step:     v1beta1.ForEachStep{Until: `false`, MaxIterations: 2},
+			items:    []string{"", ""},
+			indexes:  []int{0, 1},
+			errorMsg: "loop exceeded max iterations: condition not met within 2 iterations",
+		},
+		{
+			name:     "items exceed max iterations",
+			step:     v1beta1.ForEachStep{Items: items, MaxIterations: 2},
+			errorMsg: "loop exceeded max iterations: 3 items exceed the max iterations of 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var items []string
+			var indexes []int
+
+			steps := map[string]Step{
+				"deploy": &pipeTestStep{
+					fn: func(ctx StepContext) (StepContext, error) {
+						items = append(items, ctx.Loop.Item)
+						indexes = append(indexes, ctx.Loop.Index)
+						ctx.OutputVars.OutputVars["region"] = *v1beta1.NewStructuredValues("region-" + ctx.ToV1Beta1().Index()["context.loop.item"])
+						return ctx, nil
+					},
+				},
+			}
+
+			step := tt.step
+			step.Refs = []v1beta1.StepReference{{Name: "deploy"}}
+			spec := &v1beta1.Step{Name: "rollout", ForEach: &step}
+
+			next, err := WithForEach(celEnv)(spec).Bootstrap(&mockPipelineWithPipeSteps{steps: steps}, func(ctx StepContext) (StepContext, error) {
+				return ctx, nil
+			})
+			require.NoError(t, err)
+
+			ctx := NewContext()
+			ctx.Context = context.Background()
+			ctx.InputVars.Inputs = tt.inputs
+			ctx, err = next(ctx)
+
+			assert.Equal(t, tt.items, items)
+			assert.Equal(t, tt.indexes, indexes)
+
+			if tt.errorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.outputs, ctx.OutputVars.OutputVars["region"].ArrayVal)
+		})
+	}
+}
+
+func TestForEachSkipDone(t *testing.T) {
+	var items []string
+	run := func(ctx StepContext) (StepContext, error) {
+		items = append(items, ctx.Loop.Item)
+		return ctx, nil
+	}
+
+	spec := &v1beta1.Step{Name: "deploy"}
+	pipeline := &mockPipelineWithPipeSteps{}
+	entrypoint, err := Chain(pipeline, WithResult()(spec), WithSkipDone(true)(spec))
+	require.NoError(t, err)
+
+	pipeline.steps = map[string]Step{
+		"deploy": &pipeTestStep{
+			fn: func(ctx StepContext) (StepContext, error) {
+				ctx, err := entrypoint(ctx)
+				if err != nil {
+					return ctx, err
+				}
+
+				return run(ctx)
+			},
+		},
+	}
+
+	celEnv, err := cel.NewEnv()
+	require.NoError(t, err)
+
+	next, err := WithForEach(celEnv)(&v1beta1.Step{
+		Name: "rollout",
+		ForEach: &v1beta1.ForEachStep{
+			Items: v1beta1.NewStructuredValues("eu", "us", "ap"),
+			Refs:  []v1beta1.StepReference{{Name: "deploy"}},
+		},
+	}).Bootstrap(pipeline, func(ctx StepContext) (StepContext, error) {
+		return ctx, nil
+	})
+	require.NoError(t, err)
+
+	// The loop is executed again while a result of its last iteration was recovered
+	ctx := NewContext()
+	ctx.Context = context.Background()
+	ctx.Recover(map[string]*StepContext{
+		"deploy": {EndedAt: time.Now()},
+	})
+
+	_, err = next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"eu", "us", "ap"}, items)
+}
@@ -4,17 +4,23 @@ import (
 	"fmt"
 
 	"github.com/google/cel-go/cel"
+	"github.com/raffis/rageta/internal/changes"
 	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
 )
 
-func WithIf(celEnv *cel.Env) ProcessorBuilder {
+func WithIf(celEnv *cel.Env, detector changes.Detector) ProcessorBuilder {
 	return func(spec *v1beta1.Step) Bootstraper {
 		if len(spec.If) == 0 {
 			return nil
 		}
 
+		if detector == nil {
+			detector = changes.Always()
+		}
+
 		return &If{
 			celEnv:     celEnv,
+			detector:   detector,
 			conditions: spec.If,
 		}
 	}
@@ -26,8 +32,15 @@ var ErrConditionFalse = &pipelineError{
 	abortOnError: false,
 }
 
+var ErrUnchanged = &pipelineError{
+	message:      "step skipped as no files changed",
+	result:       "skipped-unchanged",
+	abortOnError: false,
+}
+
 type If struct {
 	celEnv     *cel.Env
+	detector   changes.Detector
 	conditions []v1beta1.IfCondition
 }
 
@@ -68,6 +81,15 @@ func (s *If) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 				if !value.Value().(bool) {
 					return ctx, ErrConditionFalse
 				}
+			case len(condition.Changed) > 0:
+				changed, err := changes.AnyChanged(s.detector, condition.Changed)
+				if err != nil {
+					return ctx, fmt.Errorf("if changed evaluation failed: %w", err)
+				}
+
+				if !changed {
+					return ctx, ErrUnchanged
+				}
 			default:
 				return ctx, fmt.Errorf("invalid if condition given")
 			}
@@ -52,7 +52,7 @@ func TestIfBuilder(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			builder := WithIf(celEnv)
+			builder := WithIf(celEnv, nil)
 			bootstraper := builder(tt.spec)
 
 			if tt.expectNil {
@@ -83,3 +83,50 @@ func TestIfBootstrapEvaluationErrors(t *testing.T) {
 	// Skip this test for now as it requires complex CEL setup
 	t.Skip("Skipping complex CEL tests - requires proper type setup")
 }
+
+type mockDetector map[string]bool
+
+func (m mockDetector) Changed(pattern string) (bool, error) {
+	return m[pattern], nil
+}
+
+func TestIfChanged(t *testing.T) {
+	detector := mockDetector{"*.go": true}
+
+	tests := []struct {
+		name     string
+		changed  []string
+		expected error
+	}{
+		{
+			name:    "matching pattern changed",
+			changed: []string{"*.md", "*.go"},
+		},
+		{
+			name:     "no pattern changed",
+			changed:  []string{"*.md"},
+			expected: ErrUnchanged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &v1beta1.Step{
+				StepOptions: v1beta1.StepOptions{
+					If: []v1beta1.IfCondition{{Changed: tt.changed}},
+				},
+			}
+
+			called := false
+			next, err := WithIf(nil, detector)(spec).Bootstrap(nil, func(ctx StepContext) (StepContext, error) {
+				called = true
+				return ctx, nil
+			})
+			require.NoError(t, err)
+
+			_, err = next(NewContext())
+			assert.Equal(t, tt.expected, err)
+			assert.Equal(t, tt.expected == nil, called)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package processor
 
 import (
+	"encoding/json"
 	"fmt"
 	"maps"
 
@@ -41,7 +42,7 @@ func (s *Inherit) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 			return ctx, err
 		}
 
-		pipe, err := s.provider.Resolve(ctx, inherit.Pipeline)
+		pipe, err := s.resolve(ctx, inherit)
 		if err != nil {
 			return ctx, fmt.Errorf("failed to open pipeline: %w", err)
 		}
@@ -70,6 +71,37 @@ func (s *Inherit) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 	}, nil
 }
 
+func (s *Inherit) resolve(ctx StepContext, inherit *v1beta1.InheritStep) (v1beta1.Pipeline, error) {
+	if inherit.Generated == nil {
+		return s.provider.Resolve(ctx, inherit.Pipeline)
+	}
+
+	var pipe v1beta1.Pipeline
+	generator, ok := ctx.Steps[inherit.Generated.Step.Name]
+	if !ok {
+		return pipe, fmt.Errorf("generating step %q has not been executed", inherit.Generated.Step.Name)
+	}
+
+	output, ok := generator.OutputVars.OutputVars[inherit.Generated.Output]
+	if !ok {
+		return pipe, fmt.Errorf("step %q did not write pipeline output %q", inherit.Generated.Step.Name, inherit.Generated.Output)
+	}
+
+	if err := json.Unmarshal([]byte(output.StringVal), &pipe); err != nil {
+		return pipe, fmt.Errorf("invalid generated pipeline: %w", err)
+	}
+
+	if err := validateGeneratedPipeline(pipe); err != nil {
+		return pipe, fmt.Errorf("invalid generated pipeline: %w", err)
+	}
+
+	if pipe.Name == "" {
+		pipe.Name = inherit.Generated.Output
+	}
+
+	return pipe, nil
+}
+
 func (s *Inherit) mapInputs(inputs []v1beta1.Param) map[string]v1beta1.ParamValue {
 	m := make(map[string]v1beta1.ParamValue)
 	for _, v := range inputs {
@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInheritResolveGenerated(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		errorMsg string
+	}{
+		{
+			name:     "pipeline",
+			manifest: `{"entrypoint":"api","steps":[{"name":"api","run":{"image":"alpine"}}]}`,
+		},
+		{
+			name:     "pipeline with extends",
+			manifest: `{"extends":"./base.yaml"}`,
+			errorMsg: "extends is not supported in generated pipelines",
+		},
+		{
+			name:     "pipeline with patches",
+			manifest: `{"patches":[{"step":{"name":"api"}}]}`,
+			errorMsg: "patches are not supported in generated pipelines",
+		},
+		{
+			name:     "pipeline with uses",
+			manifest: `{"steps":[{"name":"api","uses":"ghcr.io/raffis/rageta/steps/go"}]}`,
+			errorMsg: `step "api": uses is not supported in generated pipelines`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := NewContext()
+			generator.OutputVars.OutputVars["services"] = *v1beta1.NewStructuredValues(tt.manifest)
+
+			ctx := NewContext()
+			ctx.Steps["generate"] = &generator
+
+			inherit := &Inherit{}
+			pipe, err := inherit.resolve(ctx, &v1beta1.InheritStep{
+				Generated: &v1beta1.GeneratedPipeline{
+					Step:   v1beta1.StepReference{Name: "generate"},
+					Output: "services",
+				},
+			})
+
+			if tt.errorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "services", pipe.Name)
+			assert.Equal(t, "api", pipe.Entrypoint)
+		})
+	}
+}
@@ -5,25 +5,30 @@ import (
 	"maps"
 
 	"github.com/google/cel-go/cel"
+	"github.com/raffis/rageta/internal/substitute"
 	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
 )
 
 func WithInputVars(celEnv *cel.Env) ProcessorBuilder {
 	return func(spec *v1beta1.Step) Bootstraper {
-		if len(spec.Inputs) == 0 {
+		if len(spec.Inputs) == 0 && len(spec.With) == 0 {
 			return nil
 		}
 
 		return &InputVars{
-			celEnv: celEnv,
-			inputs: spec.Inputs,
+			celEnv:   celEnv,
+			inputs:   spec.Inputs,
+			with:     spec.With,
+			packaged: spec.Uses != "",
 		}
 	}
 }
 
 type InputVars struct {
-	celEnv *cel.Env
-	inputs []v1beta1.InputParam
+	celEnv   *cel.Env
+	inputs   []v1beta1.InputParam
+	with     []v1beta1.Param
+	packaged bool
 }
 
 type InputVarsContext struct {
@@ -60,8 +65,27 @@ func (s *InputVars) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 		maps.Copy(originInputs, ctx.InputVars.Inputs)
 
 		vars := ctx.ToV1Beta1()
+
+		// With values are substituted in the scope of the calling pipeline
+		with := make([]v1beta1.Param, len(s.with))
+		for i, param := range s.with {
+			with[i] = *param.DeepCopy()
+		}
+
+		if err := substitute.Substitute(vars, with); err != nil {
+			return ctx, fmt.Errorf("failed to substitute with values: %w", err)
+		}
+
+		provided := make(map[string]bool, len(with))
+		for _, param := range with {
+			ctx.InputVars.Inputs[param.Name] = param.Value
+			provided[param.Name] = true
+		}
+
 		for _, input := range s.inputs {
 			switch {
+			case provided[input.Name]:
+				// Already populated by the with values
 			case input.CelExpression != nil:
 				value, _, err := expr[input.Name].ContextEval(ctx, map[string]any{
 					"context": vars,
@@ -79,7 +103,8 @@ func (s *InputVars) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 				}
 
 			case input.Default != nil:
-				if _, ok := ctx.InputVars.Inputs[input.Name]; !ok {
+				// Inputs of step packages are not shadowed by inputs of the calling pipeline
+				if _, ok := ctx.InputVars.Inputs[input.Name]; !ok || s.packaged {
 					ctx.InputVars.Inputs[input.Name] = *input.Default
 				}
 			default:
@@ -0,0 +1,111 @@
+package processor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/raffis/rageta/internal/locks"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+// BlockedNotifier is notified once a step is blocked by a lock and once it continues.
+type BlockedNotifier func(ctx StepContext, blocked bool)
+
+type LocksContext struct {
+	held []string
+}
+
+func WithLock(locker locks.Locker, notify BlockedNotifier) ProcessorBuilder {
+	return func(spec *v1beta1.Step) Bootstraper {
+		if locker == nil || (spec.Lock == "" && len(spec.Semaphores) == 0) {
+			return nil
+		}
+
+		semaphores := slices.Clone(spec.Semaphores)
+		if spec.Lock != "" {
+			semaphores = append(semaphores, v1beta1.SemaphoreRef{
+				Name:     spec.Lock,
+				Capacity: 1,
+				Weight:   1,
+			})
+		}
+
+		// Locks are always acquired in the same order to avoid deadlocks between steps holding multiple locks
+		slices.SortFunc(semaphores, func(a, b v1beta1.SemaphoreRef) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+
+		return &Lock{
+			locker:     locker,
+			notify:     notify,
+			semaphores: semaphores,
+		}
+	}
+}
+
+type Lock struct {
+	locker     locks.Locker
+	notify     BlockedNotifier
+	semaphores []v1beta1.SemaphoreRef
+}
+
+func (s *Lock) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
+	return func(ctx StepContext) (StepContext, error) {
+		held := ctx.Locks.held
+		var releases []func() error
+
+		defer func() {
+			for _, release := range slices.Backward(releases) {
+				_ = release()
+			}
+		}()
+
+		var blockedSince time.Time
+		for _, semaphore := range s.semaphores {
+			// Locks are reentrant, steps executed by a step holding the lock do not acquire it again
+			if slices.Contains(held, semaphore.Name) {
+				continue
+			}
+
+			release, ok, err := s.locker.TryAcquire(semaphore.Name, semaphore.Capacity, semaphore.Weight)
+			if err != nil {
+				return ctx, err
+			}
+
+			if !ok {
+				if blockedSince.IsZero() {
+					blockedSince = time.Now()
+					if s.notify != nil {
+						s.notify(ctx, true)
+					}
+				}
+
+				_, _ = fmt.Fprintf(ctx.Events.Dev, "Task %q blocked by lock %q\n", ctx.UniqueName(), semaphore.Name)
+
+				release, err = s.locker.Acquire(ctx, semaphore.Name, semaphore.Capacity, semaphore.Weight)
+				if err != nil {
+					return ctx, fmt.Errorf("failed to acquire lock %q: %w", semaphore.Name, err)
+				}
+			}
+
+			releases = append(releases, release)
+			held = append(slices.Clone(held), semaphore.Name)
+		}
+
+		if !blockedSince.IsZero() {
+			_, _ = fmt.Fprintf(ctx.Events.Dev, "Task %q acquired locks after being blocked [%s]\n", ctx.UniqueName(), time.Since(blockedSince).Round(time.Millisecond*100))
+			if s.notify != nil {
+				s.notify(ctx, false)
+			}
+		}
+
+		parentHeld := ctx.Locks.held
+		ctx.Locks.held = held
+		ctx, err := next(ctx)
+		ctx.Locks.held = parentHeld
+
+		return ctx, err
+	}, nil
+}
@@ -0,0 +1,78 @@
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/raffis/rageta/internal/locks"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLock(t *testing.T) {
+	locker := locks.File(t.TempDir(), time.Millisecond*10)
+
+	var blocked []bool
+	notify := func(ctx StepContext, isBlocked bool) {
+		blocked = append(blocked, isBlocked)
+	}
+
+	spec := &v1beta1.Step{
+		Name: "step",
+		StepOptions: v1beta1.StepOptions{
+			Lock: "db",
+		},
+	}
+
+	var calls int
+	var next Next
+	next, err := WithLock(locker, notify)(spec).Bootstrap(nil, func(ctx StepContext) (StepContext, error) {
+		calls++
+
+		// Locks are reentrant
+		if calls == 1 {
+			return next(ctx)
+		}
+
+		return ctx, nil
+	})
+	require.NoError(t, err)
+
+	ctx := NewContext()
+	ctx.Context = context.Background()
+
+	_, err = next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, blocked)
+
+	t.Run("blocked while the lock is held", func(t *testing.T) {
+		release, err := locker.Acquire(context.Background(), "db", 1, 1)
+		require.NoError(t, err)
+
+		go func() {
+			time.Sleep(time.Millisecond * 50)
+			_ = release()
+		}()
+
+		calls = 1
+		_, err = next(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, false}, blocked)
+	})
+
+	t.Run("lock is released after the step", func(t *testing.T) {
+		release, ok, err := locker.TryAcquire("db", 1, 1)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.NoError(t, release())
+	})
+}
+
+func TestLockNotApplicable(t *testing.T) {
+	locker := locks.File(t.TempDir(), time.Millisecond*10)
+	assert.Nil(t, WithLock(locker, nil)(&v1beta1.Step{}))
+	assert.Nil(t, WithLock(nil, nil)(&v1beta1.Step{StepOptions: v1beta1.StepOptions{Lock: "db"}}))
+}
@@ -37,6 +37,10 @@ func (s *OtelTrace) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 			span.SetAttributes(attribute.String(tag.Key, tag.Value))
 		}
 
+		if attempt, ok := ctx.AttemptOf(s.stepName); ok {
+			span.SetAttributes(attribute.Int("attempt", attempt))
+		}
+
 		ctx.Context = logr.NewContext(ctx, logr.FromContextOrDiscard(ctx).WithValues(
 			"step", s.stepName,
 			"span-id", span.SpanContext().SpanID(),
@@ -11,7 +11,7 @@ type OutputFactory func(ctx StepContext, stepName, short string) (io.Writer, io.
 
 func WithOutput(outputFactory OutputFactory, withInternals, decouple bool) ProcessorBuilder {
 	return func(spec *v1beta1.Step) Bootstraper {
-		internalStep := spec.Run == nil && spec.Inherit == nil
+		internalStep := spec.Run == nil && spec.Inherit == nil && spec.Approval == nil
 
 		if !withInternals && internalStep {
 			return nil
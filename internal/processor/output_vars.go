@@ -1,12 +1,16 @@
 package processor
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path"
 
 	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/yaml"
 )
 
 func WithOutputVars() ProcessorBuilder {
@@ -71,23 +75,76 @@ func (s *OutputVars) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 			return ctx, err
 		}
 
-		for name, output := range outputs {
+		for _, spec := range s.outputs {
+			output := outputs[spec.Name]
 			_ = output.Sync()
 			b, err := io.ReadAll(output)
 			if err != nil {
 				return ctx, err
 			}
 
+			if spec.Type == v1beta1.StepOutputTypePipeline {
+				if len(bytes.TrimSpace(b)) == 0 {
+					continue
+				}
+
+				manifest, err := encodeGeneratedPipeline(b)
+				if err != nil {
+					return ctx, fmt.Errorf("pipeline output %q failed: %w", spec.Name, err)
+				}
+
+				ctx.OutputVars.OutputVars[spec.Name] = *v1beta1.NewStructuredValues(string(manifest))
+				continue
+			}
+
 			value := v1beta1.ParamValue{}
 
 			if err := value.UnmarshalJSON(b); err != nil {
 				return ctx, fmt.Errorf("param output failed: %w", err)
 			}
 
-			ctx.OutputVars.OutputVars[name] = value
+			ctx.OutputVars.OutputVars[spec.Name] = value
 		}
 
 		return ctx, err
 
 	}, nil
 }
+
+// encodeGeneratedPipeline validates a pipeline manifest written as yaml or json and returns it json encoded.
+func encodeGeneratedPipeline(manifest []byte) ([]byte, error) {
+	var pipeline v1beta1.Pipeline
+	if err := yaml.UnmarshalStrict(manifest, &pipeline); err != nil {
+		return nil, fmt.Errorf("invalid pipeline manifest: %w", err)
+	}
+
+	if pipeline.Kind != "" && pipeline.Kind != "Pipeline" {
+		return nil, fmt.Errorf("invalid pipeline manifest: expected kind Pipeline but got %s", pipeline.Kind)
+	}
+
+	if err := validateGeneratedPipeline(pipeline); err != nil {
+		return nil, fmt.Errorf("invalid pipeline manifest: %w", err)
+	}
+
+	return json.Marshal(pipeline)
+}
+
+// validateGeneratedPipeline rejects fields which are only resolved by the pipeline providers.
+// Generated pipelines are not resolved by a provider, these fields would be ignored otherwise.
+func validateGeneratedPipeline(pipeline v1beta1.Pipeline) error {
+	if pipeline.Extends != "" {
+		return errors.New("extends is not supported in generated pipelines")
+	}
+
+	if len(pipeline.Patches) > 0 {
+		return errors.New("patches are not supported in generated pipelines")
+	}
+
+	for _, step := range pipeline.Steps {
+		if step.Uses != "" {
+			return fmt.Errorf("step %q: uses is not supported in generated pipelines", step.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package processor
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputVarsBootstrap(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   v1beta1.StepOutputParam
+		content  string
+		expected func(t *testing.T, ctx StepContext)
+		errorMsg string
+	}{
+		{
+			name:    "param output",
+			output:  v1beta1.StepOutputParam{Name: "version"},
+			content: `"v1.0.0"`,
+			expected: func(t *testing.T, ctx StepContext) {
+				assert.Equal(t, "v1.0.0", ctx.OutputVars.OutputVars["version"].StringVal)
+			},
+		},
+		{
+			name:    "pipeline output",
+			output:  v1beta1.StepOutputParam{Name: "services", Type: v1beta1.StepOutputTypePipeline},
+			content: "entrypoint: api\nsteps:\n- name: api\n  run:\n    image: alpine\n",
+			expected: func(t *testing.T, ctx StepContext) {
+				var pipeline v1beta1.Pipeline
+				require.NoError(t, json.Unmarshal([]byte(ctx.OutputVars.OutputVars["services"].StringVal), &pipeline))
+				assert.Equal(t, "api", pipeline.Entrypoint)
+				assert.Equal(t, "alpine", pipeline.Steps[0].Run.Image)
+			},
+		},
+		{
+			name:    "empty pipeline output",
+			output:  v1beta1.StepOutputParam{Name: "services", Type: v1beta1.StepOutputTypePipeline},
+			content: "",
+			expected: func(t *testing.T, ctx StepContext) {
+				assert.NotContains(t, ctx.OutputVars.OutputVars, "services")
+			},
+		},
+		{
+			name:     "invalid pipeline output",
+			output:   v1beta1.StepOutputParam{Name: "services", Type: v1beta1.StepOutputTypePipeline},
+			content:  "steps:\n- name: api\n  rnu: {}\n",
+			errorMsg: `pipeline output "services" failed: invalid pipeline manifest`,
+		},
+		{
+			name:     "pipeline output of wrong kind",
+			output:   v1beta1.StepOutputParam{Name: "services", Type: v1beta1.StepOutputTypePipeline},
+			content:  "kind: StepPackage\n",
+			errorMsg: "expected kind Pipeline but got StepPackage",
+		},
+		{
+			name:     "pipeline output with extends",
+			output:   v1beta1.StepOutputParam{Name: "services", Type: v1beta1.StepOutputTypePipeline},
+			content:  "extends: ./base.yaml\n",
+			errorMsg: "extends is not supported in generated pipelines",
+		},
+		{
+			name:     "pipeline output with patches",
+			output:   v1beta1.StepOutputParam{Name: "services", Type: v1beta1.StepOutputTypePipeline},
+			content:  "patches:\n- step:\n    name: api\n",
+			errorMsg: "patches are not supported in generated pipelines",
+		},
+		{
+			name:     "pipeline output with uses",
+			output:   v1beta1.StepOutputParam{Name: "services", Type: v1beta1.StepOutputTypePipeline},
+			content:  "steps:\n- name: api\n  uses: ghcr.io/raffis/rageta/steps/go\n",
+			errorMsg: `step "api": uses is not supported in generated pipelines`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &v1beta1.Step{
+				StepOptions: v1beta1.StepOptions{
+					Outputs: []v1beta1.StepOutputParam{tt.output},
+				},
+			}
+
+			processor := WithOutputVars()(spec)
+			require.NotNil(t, processor)
+
+			next, err := processor.Bootstrap(&mockPipeline{}, func(ctx StepContext) (StepContext, error) {
+				require.Len(t, ctx.OutputVars.Outputs, 1)
+				return ctx, os.WriteFile(ctx.OutputVars.Outputs[0].Path, []byte(tt.content), 0600)
+			})
+			require.NoError(t, err)
+
+			ctx := NewContext()
+			ctx.ContextDir = t.TempDir()
+
+			ctx, err = next(ctx)
+			if tt.errorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			tt.expected(t, ctx)
+		})
+	}
+}
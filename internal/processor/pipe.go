@@ -96,8 +96,8 @@ func (s *Pipe) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 			go func() {
 				resultCtx, err := step.next(step.ctx)
 				// Normal pipe stages must close their own writer immediately so downstream readers
-				// can observe EOF. ErrConditionFalse stages are handled by forwarding logic below.
-				if step.w != nil && !errors.Is(err, ErrConditionFalse) {
+				// can observe EOF. Skipped stages are handled by forwarding logic below.
+				if step.w != nil && !errors.Is(err, ErrConditionFalse) && !errors.Is(err, ErrUnchanged) {
 					if closeErr := step.w.Close(); closeErr != nil {
 						err = closeErr
 					}
@@ -130,7 +130,7 @@ func (s *Pipe) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 				}
 			// If one pipe step is skipped instead aborting the pipe the streams from the previous step
 			// are passed to the next after the skipped one
-			case errors.Is(res.err, ErrConditionFalse):
+			case errors.Is(res.err, ErrConditionFalse), errors.Is(res.err, ErrUnchanged):
 				if res.nextStdin != nil && res.lastStdout != nil {
 					_, copyErr := io.Copy(res.nextStdin, res.lastStdout)
 					if copyErr != nil {
@@ -2,13 +2,26 @@ package processor
 
 import (
 	"context"
+	"maps"
 	"time"
 
 	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
 )
 
 type PipelineBuilder interface {
-	Build(pipeline v1beta1.Pipeline, entrypoint string, inputs map[string]v1beta1.ParamValue, stepCtx StepContext) (Executable, error)
+	Build(pipeline v1beta1.Pipeline, entrypoint string, inputs map[string]v1beta1.ParamValue, stepCtx StepContext, opts ...BuildOption) (Executable, error)
+}
+
+// BuildOption is applied to the step context a pipeline is executed with.
+type BuildOption func(stepCtx *StepContext)
+
+// RecoverSteps keeps the step results and containers of a previous execution.
+// A pipeline is executed without any step results otherwise, including pipelines inherited by a step.
+func RecoverSteps(from StepContext) BuildOption {
+	return func(stepCtx *StepContext) {
+		maps.Copy(stepCtx.Steps, from.Steps)
+		maps.Copy(stepCtx.Containers, from.Containers)
+	}
 }
 
 type Executable func() (StepContext, map[string]v1beta1.ParamValue, error)
@@ -102,7 +102,10 @@ func (s *Result) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 				}
 			}
 
-			ctx.Error = err
+			// Steps skipped as done are recorded as successful again
+			if !errors.Is(err, ErrSkipDone) {
+				ctx.Error = err
+			}
 		}
 
 		ctx.Steps[s.stepName] = &ctx
@@ -2,33 +2,122 @@ package processor
 
 import (
 	"context"
+	"maps"
+	"slices"
 	"time"
 
+	"github.com/google/cel-go/cel"
 	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
 	"github.com/sethvargo/go-retry"
 )
 
-func WithRetry() ProcessorBuilder {
+type RetryContext struct {
+	StepName string
+	Attempt  int
+}
+
+// AttemptOf returns the attempt number if the context belongs to the given step with a retry policy.
+func (c StepContext) AttemptOf(stepName string) (int, bool) {
+	if c.Retry.StepName == "" || c.Retry.StepName != stepName {
+		return 0, false
+	}
+
+	return c.Retry.Attempt, true
+}
+
+func WithRetry(celEnv *cel.Env) ProcessorBuilder {
 	return func(spec *v1beta1.Step) Bootstraper {
 		if spec.Retry == nil || (spec.Retry.Constant.Duration == 0 && spec.Retry.Exponential.Duration == 0) {
 			return nil
 		}
 
 		return &Retry{
+			stepName:    spec.Name,
+			celEnv:      celEnv,
 			max:         uint64(spec.Retry.MaxRetries),
 			exponential: spec.Retry.Exponential.Duration,
 			constant:    spec.Retry.Constant.Duration,
+			jitter:      spec.Retry.Jitter.Duration,
+			maxDuration: spec.Retry.MaxDuration.Duration,
+			retryOn:     spec.Retry.RetryOn,
+			reset:       spec.Retry.ResetBetweenAttempts,
 		}
 	}
 }
 
 type Retry struct {
+	stepName    string
+	celEnv      *cel.Env
 	max         uint64
 	exponential time.Duration
 	constant    time.Duration
+	jitter      time.Duration
+	maxDuration time.Duration
+	retryOn     *v1beta1.RetryOn
+	reset       bool
 }
 
 func (s *Retry) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
+	var retryOn *failureCondition
+	if s.retryOn != nil {
+		condition, err := newFailureCondition(s.celEnv, "retry", s.retryOn.ExitCodes, s.retryOn.CelExpression)
+		if err != nil {
+			return nil, err
+		}
+
+		retryOn = condition
+	}
+
+	return func(stepCtx StepContext) (StepContext, error) {
+		envs := maps.Clone(stepCtx.EnvVars.Envs)
+		outputVars := maps.Clone(stepCtx.OutputVars.OutputVars)
+		outputs := slices.Clone(stepCtx.OutputVars.Outputs)
+		parentRetry := stepCtx.Retry
+
+		var err error
+		attempt := 0
+
+		if retryErr := retry.Do(stepCtx, s.backoff(), func(ctx context.Context) error {
+			attempt++
+
+			if attempt > 1 && s.reset {
+				stepCtx.EnvVars.Envs = maps.Clone(envs)
+				stepCtx.OutputVars.OutputVars = maps.Clone(outputVars)
+				stepCtx.OutputVars.Outputs = slices.Clone(outputs)
+			}
+
+			stepCtx.Context = ctx
+			stepCtx.Retry = RetryContext{
+				StepName: s.stepName,
+				Attempt:  attempt,
+			}
+
+			stepCtx, err = next(stepCtx)
+			if err == nil {
+				return nil
+			}
+
+			retryable, evalErr := s.retryable(retryOn, stepCtx, attempt, err)
+			if evalErr != nil {
+				err = evalErr
+				return err
+			}
+
+			if !retryable {
+				return err
+			}
+
+			return retry.RetryableError(err)
+		}); retryErr != nil && err == nil {
+			err = retryErr
+		}
+
+		stepCtx.Retry = parentRetry
+		return stepCtx, err
+	}, nil
+}
+
+func (s *Retry) backoff() retry.Backoff {
 	var backoff retry.Backoff
 	switch {
 	case s.exponential != 0:
@@ -37,24 +126,31 @@ func (s *Retry) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 		backoff = retry.NewConstant(s.constant)
 	}
 
+	if s.jitter != 0 {
+		backoff = retry.WithJitter(s.jitter, backoff)
+	}
+
+	if s.maxDuration != 0 {
+		backoff = retry.WithMaxDuration(s.maxDuration, backoff)
+	}
+
 	if s.max != 0 {
 		backoff = retry.WithMaxRetries(s.max, backoff)
 	}
 
-	return func(stepCtx StepContext) (StepContext, error) {
-		var err error
-		if err := retry.Do(stepCtx, backoff, func(ctx context.Context) error {
-			stepCtx.Context = ctx
-			stepCtx, err = next(stepCtx)
-			if err != nil {
-				return retry.RetryableError(err)
-			}
+	return backoff
+}
 
-			return nil
-		}); err != nil {
-			return stepCtx, err
-		}
+// retryable reports whether a failed attempt is retried according to the retryOn conditions.
+// Skipped steps are never retried.
+func (s *Retry) retryable(retryOn *failureCondition, ctx StepContext, attempt int, err error) (bool, error) {
+	if !AbortOnError(err) {
+		return false, nil
+	}
 
-		return stepCtx, err
-	}, nil
+	if retryOn == nil || retryOn.empty() {
+		return true, nil
+	}
+
+	return retryOn.matches(ctx, attempt, err)
 }
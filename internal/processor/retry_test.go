@@ -3,9 +3,14 @@ package processor
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"reflect"
 	"testing"
 	"time"
 
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
 	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -64,7 +69,7 @@ func TestRetryBuilder(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			builder := WithRetry()
+			builder := WithRetry(nil)
 			bootstraper := builder(tt.spec)
 
 			if tt.expectNil {
@@ -237,3 +242,141 @@ func TestRetryBackoffStrategies(t *testing.T) {
 		})
 	}
 }
+
+func TestRetryOn(t *testing.T) {
+	celEnv, err := cel.NewEnv(
+		ext.NativeTypes(ext.ParseStructTags(true),
+			reflect.TypeOf(&v1beta1.Context{}),
+			reflect.TypeOf(&v1beta1.Attempt{}),
+		),
+		cel.Variable("context", cel.ObjectType("v1beta1.Context")),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		retryOn       *v1beta1.RetryOn
+		nextError     error
+		expectedCalls int
+	}{
+		{
+			name:          "every failure is retried without retryOn",
+			nextError:     errors.New("test error"),
+			expectedCalls: 3,
+		},
+		{
+			name:          "skipped steps are not retried",
+			nextError:     ErrConditionFalse,
+			expectedCalls: 1,
+		},
+		{
+			name:          "matching exit code is retried",
+			retryOn:       &v1beta1.RetryOn{ExitCodes: []int{6, 7}},
+			nextError:     &ContainerError{exitCode: 7, err: errors.New("exit code")},
+			expectedCalls: 3,
+		},
+		{
+			name:          "other exit codes are not retried",
+			retryOn:       &v1beta1.RetryOn{ExitCodes: []int{6, 7}},
+			nextError:     &ContainerError{exitCode: 1, err: errors.New("exit code")},
+			expectedCalls: 1,
+		},
+		{
+			name:          "errors without exit code are not retried with exit codes",
+			retryOn:       &v1beta1.RetryOn{ExitCodes: []int{6, 7}},
+			nextError:     errors.New("test error"),
+			expectedCalls: 1,
+		},
+		{
+			name:          "cel expression matching the error is retried",
+			retryOn:       &v1beta1.RetryOn{CelExpression: `context.attempt.error.contains("connection reset") && context.attempt.number < 2`},
+			nextError:     errors.New("read: connection reset by peer"),
+			expectedCalls: 2,
+		},
+		{
+			name:          "cel expression over the exit code",
+			retryOn:       &v1beta1.RetryOn{CelExpression: `context.attempt.exitCode == 7`},
+			nextError:     &ContainerError{exitCode: 7, err: errors.New("exit code")},
+			expectedCalls: 3,
+		},
+		{
+			name:          "cel expression not matching is not retried",
+			retryOn:       &v1beta1.RetryOn{CelExpression: `context.attempt.error.contains("connection reset")`},
+			nextError:     errors.New("syntax error"),
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &v1beta1.Step{
+				Name: "step",
+				StepOptions: v1beta1.StepOptions{
+					Retry: &v1beta1.Retry{
+						Constant:   metav1.Duration{Duration: time.Millisecond},
+						MaxRetries: 2,
+						RetryOn:    tt.retryOn,
+					},
+				},
+			}
+
+			var attempts []int
+			nextFunc, err := WithRetry(celEnv)(spec).Bootstrap(&mockPipeline{}, func(ctx StepContext) (StepContext, error) {
+				attempt, ok := ctx.AttemptOf("step")
+				assert.True(t, ok)
+				attempts = append(attempts, attempt)
+				return ctx, tt.nextError
+			})
+			require.NoError(t, err)
+
+			_, err = nextFunc(StepContext{Context: context.Background()})
+			assert.ErrorIs(t, err, tt.nextError)
+
+			var expectedAttempts []int
+			for i := 1; i <= tt.expectedCalls; i++ {
+				expectedAttempts = append(expectedAttempts, i)
+			}
+
+			assert.Equal(t, expectedAttempts, attempts)
+		})
+	}
+}
+
+func TestRetryResetBetweenAttempts(t *testing.T) {
+	for _, reset := range []bool{true, false} {
+		t.Run(fmt.Sprintf("reset %t", reset), func(t *testing.T) {
+			spec := &v1beta1.Step{
+				Name: "step",
+				StepOptions: v1beta1.StepOptions{
+					Retry: &v1beta1.Retry{
+						Constant:             metav1.Duration{Duration: time.Millisecond},
+						MaxRetries:           1,
+						ResetBetweenAttempts: reset,
+					},
+				},
+			}
+
+			var seen []map[string]string
+			nextFunc, err := WithRetry(nil)(spec).Bootstrap(&mockPipeline{}, func(ctx StepContext) (StepContext, error) {
+				seen = append(seen, maps.Clone(ctx.EnvVars.Envs))
+				ctx.EnvVars.Envs["FOO"] = "bar"
+				return ctx, errors.New("test error")
+			})
+			require.NoError(t, err)
+
+			ctx := NewContext()
+			ctx.Context = context.Background()
+			_, err = nextFunc(ctx)
+			require.Error(t, err)
+
+			require.Len(t, seen, 2)
+			assert.Empty(t, seen[0])
+
+			if reset {
+				assert.Empty(t, seen[1])
+			} else {
+				assert.Equal(t, map[string]string{"FOO": "bar"}, seen[1])
+			}
+		})
+	}
+}
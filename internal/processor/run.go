@@ -18,7 +18,7 @@ import (
 	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
 )
 
-func WithRun(defaultPullPolicy runtime.PullImagePolicy, driver runtime.Interface, outputFactory OutputFactory, teardown chan Teardown) ProcessorBuilder {
+func WithRun(defaultPullPolicy runtime.PullImagePolicy, driver runtime.Interface, outputFactory OutputFactory, teardown chan Teardown, debugger Debugger) ProcessorBuilder {
 	return func(spec *v1beta1.Step) Bootstraper {
 		if spec.Run == nil {
 			return nil
@@ -30,6 +30,7 @@ func WithRun(defaultPullPolicy runtime.PullImagePolicy, driver runtime.Interface
 			driver:            driver,
 			defaultPullPolicy: defaultPullPolicy,
 			teardown:          teardown,
+			debugger:          debugger,
 		}
 	}
 }
@@ -44,6 +45,7 @@ type Run struct {
 	driver            runtime.Interface
 	defaultPullPolicy runtime.PullImagePolicy
 	teardown          chan Teardown
+	debugger          Debugger
 }
 
 func (s *Run) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
@@ -136,6 +138,35 @@ func (s *Run) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 		_, _ = ctx.Events.Dev.Write([]byte(fmt.Sprintf("🐋 starting %s", container.Image) + "\n"))
 		ctx, err := s.exec(ctx, pod)
 
+		for err != nil && s.debuggable(ctx, pod) {
+			action, debugErr := s.debugger.Debug(ctx, DebugRequest{
+				StepName: s.stepName,
+				Pod:      pod,
+				Error:    err,
+			})
+
+			if debugErr != nil {
+				return ctx, errors.Join(err, fmt.Errorf("failed to debug step: %w", debugErr))
+			}
+
+			if action == DebugActionSkip {
+				return ctx, ErrSkipDebug
+			}
+
+			if action != DebugActionRetry {
+				break
+			}
+
+			// The failed container is replaced by the retried one
+			if err := s.driver.DeletePod(ctx, pod, 0); err != nil {
+				return ctx, fmt.Errorf("failed to delete failed container: %w", err)
+			}
+
+			pod.Status = runtime.PodStatus{}
+			_, _ = ctx.Events.Dev.Write([]byte(fmt.Sprintf("🐋 retrying %s", container.Image) + "\n"))
+			ctx, err = s.exec(ctx, pod)
+		}
+
 		if err != nil {
 			var exitCode int
 			var runtimeErr ExitCode
@@ -155,6 +186,12 @@ func (s *Run) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 	}, nil
 }
 
+// debuggable returns true if the failed container can be debugged.
+// Services are not debugged and neither are steps which were cancelled or did not start a container.
+func (s *Run) debuggable(ctx StepContext, pod *runtime.Pod) bool {
+	return s.debugger != nil && s.step.Await != v1beta1.AwaitStatusReady && ctx.Err() == nil && len(pod.Status.Containers) > 0
+}
+
 type ContainerError struct {
 	containerName string
 	image         string
@@ -1,6 +1,10 @@
 package processor
 
 import (
+	"maps"
+	"os"
+
+	"github.com/joho/godotenv"
 	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
 )
 
@@ -28,18 +32,35 @@ var ErrSkipDone = &pipelineError{
 
 func (s *SkipDone) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 	return func(ctx StepContext) (StepContext, error) {
-		skipExecution := false
-		for stepName, result := range ctx.Steps {
-			if stepName == s.stepName && result.EndedAt.IsZero() && result.Error != nil {
-				skipExecution = true
-				break
-			}
+		// Results are recorded by step name, iterations of a loop can't be told apart and are always executed
+		result, ok := ctx.Steps[s.stepName]
+		if !ok || !result.recovered || ctx.Loop.active || result.EndedAt.IsZero() || result.Error != nil {
+			return next(ctx)
 		}
 
-		if !skipExecution {
-			return next(ctx)
+		// The recorded envs and secrets are exported again as if the step was executed
+		if err := writeVars(ctx.EnvVars.OutputPath, result.EnvVars.Envs); err != nil {
+			return ctx, err
 		}
 
+		if err := writeVars(ctx.SecretVars.OutputPath, result.SecretVars.Secrets); err != nil {
+			return ctx, err
+		}
+
+		maps.Copy(ctx.OutputVars.OutputVars, result.OutputVars.OutputVars)
 		return ctx, ErrSkipDone
 	}, nil
 }
+
+func writeVars(path string, vars map[string]string) error {
+	if path == "" || len(vars) == 0 {
+		return nil
+	}
+
+	content, err := godotenv.Marshal(vars)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(content), 0600)
+}
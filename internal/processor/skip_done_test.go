@@ -1,6 +1,8 @@
 package processor
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -95,18 +97,44 @@ func TestSkipDoneBootstrap(t *testing.T) {
 					EndedAt: time.Time{}, // Zero time
 				},
 			},
+			expectSkip:    false,
+			expectError:   false,
+			expectedCalls: 1,
+		},
+		{
+			name:     "matching step without error and not ended",
+			stepName: "test-step",
+			steps: map[string]*StepContext{
+				"test-step": {
+					Error:   nil,
+					EndedAt: time.Time{}, // Zero time
+				},
+			},
+			expectSkip:    false,
+			expectError:   false,
+			expectedCalls: 1,
+		},
+		{
+			name:     "matching step without error and ended",
+			stepName: "test-step",
+			steps: map[string]*StepContext{
+				"test-step": {
+					recovered: true,
+					Error:     nil,
+					EndedAt:   time.Now(),
+				},
+			},
 			expectSkip:    true,
 			expectError:   true,
 			errorType:     ErrSkipDone,
 			expectedCalls: 0,
 		},
 		{
-			name:     "matching step without error",
+			name:     "matching step done by the current execution",
 			stepName: "test-step",
 			steps: map[string]*StepContext{
 				"test-step": {
-					Error:   nil,
-					EndedAt: time.Time{}, // Zero time
+					EndedAt: time.Now(),
 				},
 			},
 			expectSkip:    false,
@@ -118,8 +146,8 @@ func TestSkipDoneBootstrap(t *testing.T) {
 			stepName: "",
 			steps: map[string]*StepContext{
 				"": {
-					Error:   assert.AnError,
-					EndedAt: time.Time{},
+					recovered: true,
+					EndedAt:   time.Now(),
 				},
 			},
 			expectSkip:    true,
@@ -128,16 +156,17 @@ func TestSkipDoneBootstrap(t *testing.T) {
 			expectedCalls: 0,
 		},
 		{
-			name:     "multiple steps, one matching with error",
+			name:     "multiple steps, one matching which is done",
 			stepName: "test-step",
 			steps: map[string]*StepContext{
 				"step1": {
 					Error:   assert.AnError,
-					EndedAt: time.Time{},
+					EndedAt: time.Now(),
 				},
 				"test-step": {
-					Error:   assert.AnError,
-					EndedAt: time.Time{},
+					recovered: true,
+					Error:     nil,
+					EndedAt:   time.Now(),
 				},
 				"step2": {
 					Error:   nil,
@@ -215,3 +244,49 @@ func TestSkipDoneErrorPropagation(t *testing.T) {
 	assert.ErrorIs(t, resultErr, assert.AnError)
 	assert.Equal(t, inputCtx, resultCtx)
 }
+
+func TestSkipDoneRestoresRecordedResult(t *testing.T) {
+	skipDone := &SkipDone{stepName: "test-step"}
+	nextFunc, err := skipDone.Bootstrap(&mockPipeline{}, func(ctx StepContext) (StepContext, error) {
+		return ctx, nil
+	})
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	ctx := NewContext()
+	ctx.EnvVars.OutputPath = filepath.Join(tmpDir, "env")
+	ctx.SecretVars.OutputPath = filepath.Join(tmpDir, "secret")
+	ctx.Steps["test-step"] = &StepContext{
+		recovered: true,
+		EndedAt:   time.Now(),
+		EnvVars: EnvVarsContext{
+			Envs: map[string]string{"FOO": "bar baz"},
+		},
+		SecretVars: SecretVarsContext{
+			Secrets: map[string]string{"TOKEN": "secret"},
+		},
+		OutputVars: OutputVarsContext{
+			OutputVars: map[string]v1beta1.ParamValue{
+				"binary": *v1beta1.NewStructuredValues("app"),
+			},
+		},
+	}
+
+	resultCtx, err := nextFunc(ctx)
+	require.ErrorIs(t, err, ErrSkipDone)
+	assert.Equal(t, *v1beta1.NewStructuredValues("app"), resultCtx.OutputVars.OutputVars["binary"])
+
+	envs, err := os.Open(ctx.EnvVars.OutputPath)
+	require.NoError(t, err)
+	defer envs.Close()
+	vars, err := parseVars(envs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"FOO": "bar baz"}, vars)
+
+	secrets, err := os.Open(ctx.SecretVars.OutputPath)
+	require.NoError(t, err)
+	defer secrets.Close()
+	vars, err = parseVars(secrets)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"TOKEN": "secret"}, vars)
+}
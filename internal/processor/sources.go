@@ -0,0 +1,53 @@
+package processor
+
+import (
+	"fmt"
+
+	"github.com/raffis/rageta/internal/changes"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+// WithSources skips steps if none of their declared sources changed.
+func WithSources(detector changes.Detector) ProcessorBuilder {
+	return func(spec *v1beta1.Step) Bootstraper {
+		if len(spec.Sources) == 0 || detector == nil {
+			return nil
+		}
+
+		var patterns []string
+		for _, source := range spec.Sources {
+			if source.Match != "" {
+				patterns = append(patterns, source.Match)
+			}
+		}
+
+		if len(patterns) == 0 {
+			return nil
+		}
+
+		return &Sources{
+			detector: detector,
+			patterns: patterns,
+		}
+	}
+}
+
+type Sources struct {
+	detector changes.Detector
+	patterns []string
+}
+
+func (s *Sources) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
+	return func(ctx StepContext) (StepContext, error) {
+		changed, err := changes.AnyChanged(s.detector, s.patterns)
+		if err != nil {
+			return ctx, fmt.Errorf("sources change detection failed: %w", err)
+		}
+
+		if !changed {
+			return ctx, ErrUnchanged
+		}
+
+		return next(ctx)
+	}, nil
+}
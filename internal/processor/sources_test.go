@@ -0,0 +1,47 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSources(t *testing.T) {
+	detector := mockDetector{"src/**": true}
+
+	assert.Nil(t, WithSources(detector)(&v1beta1.Step{}))
+	assert.Nil(t, WithSources(nil)(&v1beta1.Step{StepOptions: v1beta1.StepOptions{Sources: []v1beta1.Source{{Match: "src/**"}}}}))
+
+	tests := []struct {
+		name     string
+		sources  []v1beta1.Source
+		expected error
+	}{
+		{
+			name:    "sources changed",
+			sources: []v1beta1.Source{{Match: "docs/**"}, {Match: "src/**"}},
+		},
+		{
+			name:     "sources unchanged",
+			sources:  []v1beta1.Source{{Match: "docs/**"}},
+			expected: ErrUnchanged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			next, err := WithSources(detector)(&v1beta1.Step{StepOptions: v1beta1.StepOptions{Sources: tt.sources}}).Bootstrap(nil, func(ctx StepContext) (StepContext, error) {
+				called = true
+				return ctx, nil
+			})
+			require.NoError(t, err)
+
+			_, err = next(NewContext())
+			assert.Equal(t, tt.expected, err)
+			assert.Equal(t, tt.expected == nil, called)
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package processor
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+type SwitchContext struct {
+	StepName string
+	Case     string
+	skipped  bool
+}
+
+// SwitchOf returns the selected case if the context belongs to the given switch step.
+func (c StepContext) SwitchOf(stepName string) (string, bool) {
+	if c.Switch.StepName == "" || c.Switch.StepName != stepName {
+		return "", false
+	}
+
+	return c.Switch.Case, true
+}
+
+func WithSwitch(celEnv *cel.Env) ProcessorBuilder {
+	return func(spec *v1beta1.Step) Bootstraper {
+		if spec.Switch == nil {
+			return nil
+		}
+
+		return &Switch{
+			stepName: spec.Name,
+			celEnv:   celEnv,
+			step:     *spec.Switch,
+		}
+	}
+}
+
+type Switch struct {
+	stepName string
+	celEnv   *cel.Env
+	step     v1beta1.SwitchStep
+}
+
+type switchCase struct {
+	name  string
+	expr  cel.Program
+	steps []Step
+}
+
+func (s *Switch) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
+	var cases []switchCase
+
+	for _, c := range s.step.Cases {
+		ast, issues := s.celEnv.Compile(c.CelExpression)
+		if issues != nil && issues.Err() != nil {
+			return nil, fmt.Errorf("switch case expression compilation `%s` failed: %w", c.CelExpression, issues.Err())
+		}
+
+		prg, err := s.celEnv.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("switch case expression ast `%s` failed: %w", c.CelExpression, err)
+		}
+
+		steps, err := filterSteps(refSlice(c.Refs), pipeline)
+		if err != nil {
+			return nil, err
+		}
+
+		name := c.Name
+		if name == "" {
+			name = c.CelExpression
+		}
+
+		cases = append(cases, switchCase{
+			name:  name,
+			expr:  prg,
+			steps: steps,
+		})
+	}
+
+	if s.step.Default != nil {
+		steps, err := filterSteps(refSlice(s.step.Default.Refs), pipeline)
+		if err != nil {
+			return nil, err
+		}
+
+		cases = append(cases, switchCase{
+			name:  "default",
+			steps: steps,
+		})
+	}
+
+	return func(ctx StepContext) (StepContext, error) {
+		selected := -1
+		vars := ctx.ToV1Beta1()
+
+		for i, c := range cases {
+			if c.expr == nil {
+				selected = i
+				break
+			}
+
+			value, _, err := c.expr.ContextEval(ctx, map[string]any{
+				"context": vars,
+			})
+
+			if err != nil {
+				return ctx, fmt.Errorf("switch case expression evaluation `%s` failed: %w", c.name, err)
+			}
+
+			matched, ok := value.Value().(bool)
+			if !ok {
+				return ctx, fmt.Errorf("switch case expression `%s` must evaluate to a bool", c.name)
+			}
+
+			if matched {
+				selected = i
+				break
+			}
+		}
+
+		selectedCase := "none"
+		if selected != -1 {
+			selectedCase = cases[selected].name
+		}
+
+		_, _ = fmt.Fprintf(ctx.Events.Dev, "Task %q selected case %q\n", ctx.UniqueName(), selectedCase)
+
+		for i, c := range cases {
+			if i == selected {
+				continue
+			}
+
+			// The steps of the cases which are not selected are reported as skipped
+			for _, step := range c.steps {
+				entrypoint, err := step.Entrypoint()
+				if err != nil {
+					return ctx, err
+				}
+
+				skipCtx := ctx
+				skipCtx.Switch.skipped = true
+				_, _ = entrypoint(skipCtx)
+			}
+		}
+
+		if selected != -1 {
+			for _, step := range cases[selected].steps {
+				entrypoint, err := step.Entrypoint()
+				if err != nil {
+					return ctx, err
+				}
+
+				ctx, err = entrypoint(ctx)
+				if AbortOnError(err) {
+					return ctx, err
+				}
+			}
+		}
+
+		ctx.Switch = SwitchContext{
+			StepName: s.stepName,
+			Case:     selectedCase,
+		}
+
+		return next(ctx)
+	}, nil
+}
+
+func WithSwitchSkip() ProcessorBuilder {
+	return func(spec *v1beta1.Step) Bootstraper {
+		return &SwitchSkip{}
+	}
+}
+
+var ErrSwitchCaseSkipped = &pipelineError{
+	message:      "switch case not selected",
+	result:       "skipped-switch",
+	abortOnError: false,
+}
+
+// SwitchSkip skips steps of switch cases which were not selected.
+type SwitchSkip struct{}
+
+func (s *SwitchSkip) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
+	return func(ctx StepContext) (StepContext, error) {
+		if ctx.Switch.skipped {
+			return ctx, ErrSwitchCaseSkipped
+		}
+
+		return next(ctx)
+	}, nil
+}
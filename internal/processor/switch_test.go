@@ -0,0 +1,122 @@
+package processor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwitch(t *testing.T) {
+	celEnv, err := cel.NewEnv(
+		cel.Variable("context", cel.DynType),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		step     v1beta1.SwitchStep
+		selected string
+		executed []string
+		skipped  []string
+		errorMsg string
+	}{
+		{
+			name: "first matching case is executed",
+			step: v1beta1.SwitchStep{
+				Cases: []v1beta1.SwitchCase{
+					{Name: "a", CelExpression: "1 == 2", Refs: []v1beta1.StepReference{{Name: "a"}}},
+					{Name: "b", CelExpression: "true", Refs: []v1beta1.StepReference{{Name: "b"}}},
+					{CelExpression: "2 == 2", Refs: []v1beta1.StepReference{{Name: "c"}}},
+				},
+				Default: &v1beta1.SwitchDefault{Refs: []v1beta1.StepReference{{Name: "d"}}},
+			},
+			selected: "b",
+			executed: []string{"b"},
+			skipped:  []string{"a", "c", "d"},
+		},
+		{
+			name: "default is executed without a matching case",
+			step: v1beta1.SwitchStep{
+				Cases: []v1beta1.SwitchCase{
+					{CelExpression: "1 == 2", Refs: []v1beta1.StepReference{{Name: "a"}}},
+				},
+				Default: &v1beta1.SwitchDefault{Refs: []v1beta1.StepReference{{Name: "c"}, {Name: "d"}}},
+			},
+			selected: "default",
+			executed: []string{"c", "d"},
+			skipped:  []string{"a"},
+		},
+		{
+			name: "nothing is executed without a matching case and default",
+			step: v1beta1.SwitchStep{
+				Cases: []v1beta1.SwitchCase{
+					{CelExpression: "1 == 2", Refs: []v1beta1.StepReference{{Name: "a"}}},
+				},
+			},
+			selected: "none",
+			skipped:  []string{"a"},
+		},
+		{
+			name: "case expression must evaluate to a bool",
+			step: v1beta1.SwitchStep{
+				Cases: []v1beta1.SwitchCase{
+					{CelExpression: "'a'", Refs: []v1beta1.StepReference{{Name: "a"}}},
+				},
+			},
+			errorMsg: "switch case expression `'a'` must evaluate to a bool",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var executed, skipped []string
+			steps := make(map[string]Step)
+			for _, name := range []string{"a", "b", "c", "d"} {
+				skip, err := (&SwitchSkip{}).Bootstrap(nil, func(ctx StepContext) (StepContext, error) {
+					executed = append(executed, name)
+					return ctx, nil
+				})
+				require.NoError(t, err)
+
+				steps[name] = &pipeTestStep{
+					fn: func(ctx StepContext) (StepContext, error) {
+						ctx, err := skip(ctx)
+						if err == ErrSwitchCaseSkipped {
+							skipped = append(skipped, name)
+						}
+
+						return ctx, err
+					},
+				}
+			}
+
+			spec := &v1beta1.Step{Name: "switch", Switch: &tt.step}
+			next, err := WithSwitch(celEnv)(spec).Bootstrap(&mockPipelineWithPipeSteps{steps: steps}, func(ctx StepContext) (StepContext, error) {
+				return ctx, nil
+			})
+			require.NoError(t, err)
+
+			ctx := NewContext()
+			ctx.Context = context.Background()
+			ctx, err = next(ctx)
+
+			if tt.errorMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.executed, executed)
+			assert.Equal(t, tt.skipped, skipped)
+
+			selected, ok := ctx.SwitchOf("switch")
+			assert.True(t, ok)
+			assert.Equal(t, tt.selected, selected)
+		})
+	}
+}
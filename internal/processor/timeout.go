@@ -3,52 +3,54 @@ package processor
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
 )
 
+// WithTimeout enforces the step timeout and reports steps which exceeded the pipeline deadline as timed out.
 func WithTimeout() ProcessorBuilder {
 	return func(spec *v1beta1.Step) Bootstraper {
-		if spec.Timeout.Duration == 0 {
-			return nil
-		}
-
 		return &Timeout{
-			timer: time.NewTimer(spec.Timeout.Duration),
+			timeout: spec.Timeout.Duration,
 		}
 	}
 }
 
-var ErrTimeout = errors.New("operation timed out")
+var ErrTimeout = &pipelineError{
+	message:      "operation timed out",
+	result:       "timeout",
+	abortOnError: true,
+}
 
 type Timeout struct {
-	timer *time.Timer
+	timeout time.Duration
 }
 
 func (s *Timeout) Bootstrap(pipeline Pipeline, next Next) (Next, error) {
 	return func(ctx StepContext) (StepContext, error) {
-		copyCtx := ctx
-
-		var cancel context.CancelFunc
-		copyCtx.Context, cancel = context.WithCancel(ctx)
-		done := make(chan result)
+		parent := ctx.Context
+		stepCtx, cancel := context.WithCancel(parent)
+		if s.timeout > 0 {
+			cancel()
+			stepCtx, cancel = context.WithTimeout(parent, s.timeout)
+		}
 
 		defer cancel()
 
-		go func() {
-			ctx, err := next(copyCtx)
-			done <- result{ctx, err}
-		}()
-
-		select {
-		case <-ctx.Done():
-			return ctx, ctx.Err()
-		case <-s.timer.C:
-			return ctx, ErrTimeout
-		case result := <-done:
-			result.ctx.Context = ctx.Context
-			return result.ctx, result.err
+		// The step is awaited even after the timeout so its containers are known to the garbage collector
+		ctx.Context = stepCtx
+		ctx, err := next(ctx)
+		ctx.Context = parent
+
+		switch {
+		case err == nil || errors.Is(err, ErrTimeout) || !errors.Is(stepCtx.Err(), context.DeadlineExceeded):
+			return ctx, err
+		case errors.Is(parent.Err(), context.DeadlineExceeded):
+			return ctx, fmt.Errorf("%w: pipeline deadline exceeded", ErrTimeout)
+		default:
+			return ctx, fmt.Errorf("%w after %s", ErrTimeout, s.timeout)
 		}
 	}, nil
 }
@@ -2,6 +2,7 @@ package processor
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -13,32 +14,22 @@ import (
 
 func TestTimeoutBuilder(t *testing.T) {
 	tests := []struct {
-		name      string
-		spec      *v1beta1.Step
-		expectNil bool
+		name    string
+		spec    *v1beta1.Step
+		timeout time.Duration
 	}{
 		{
-			name: "timeout duration zero returns nil",
-			spec: &v1beta1.Step{
-				StepOptions: v1beta1.StepOptions{
-					Timeout: metav1.Duration{Duration: 0},
-				},
-			},
-			expectNil: true,
-		},
-		{
-			name: "timeout duration set returns Timeout struct",
+			name: "timeout duration set",
 			spec: &v1beta1.Step{
 				StepOptions: v1beta1.StepOptions{
 					Timeout: metav1.Duration{Duration: 5 * time.Second},
 				},
 			},
-			expectNil: false,
+			timeout: 5 * time.Second,
 		},
 		{
-			name:      "timeout not set returns nil",
-			spec:      &v1beta1.Step{},
-			expectNil: true,
+			name: "timeout not set is still built to report the pipeline deadline",
+			spec: &v1beta1.Step{},
 		},
 	}
 
@@ -47,14 +38,9 @@ func TestTimeoutBuilder(t *testing.T) {
 			builder := WithTimeout()
 			bootstraper := builder(tt.spec)
 
-			if tt.expectNil {
-				assert.Nil(t, bootstraper)
-			} else {
-				assert.NotNil(t, bootstraper)
-				timeout, ok := bootstraper.(*Timeout)
-				assert.True(t, ok)
-				assert.NotNil(t, timeout.timer)
-			}
+			timeout, ok := bootstraper.(*Timeout)
+			assert.True(t, ok)
+			assert.Equal(t, tt.timeout, timeout.timeout)
 		})
 	}
 }
@@ -63,42 +49,63 @@ func TestTimeoutBootstrap(t *testing.T) {
 	tests := []struct {
 		name        string
 		timeout     time.Duration
+		deadline    time.Duration
 		nextDelay   time.Duration
-		expectError bool
+		nextErr     error
+		expectError string
 	}{
 		{
-			name:        "next function completes within timeout",
-			timeout:     100 * time.Millisecond,
-			nextDelay:   10 * time.Millisecond,
-			expectError: false,
+			name:      "next function completes within timeout",
+			timeout:   100 * time.Millisecond,
+			nextDelay: 10 * time.Millisecond,
 		},
 		{
-			name:        "next function completes immediately",
-			timeout:     100 * time.Millisecond,
-			nextDelay:   0,
-			expectError: false,
+			name:      "next function completes immediately",
+			timeout:   100 * time.Millisecond,
+			nextDelay: 0,
 		},
 		{
 			name:        "next function exceeds timeout",
 			timeout:     10 * time.Millisecond,
 			nextDelay:   10000 * time.Millisecond,
-			expectError: true,
+			expectError: "operation timed out after 10ms",
+		},
+		{
+			name:        "next function exceeds pipeline deadline",
+			timeout:     time.Second,
+			deadline:    10 * time.Millisecond,
+			nextDelay:   10000 * time.Millisecond,
+			expectError: "operation timed out: pipeline deadline exceeded",
+		},
+		{
+			name:        "next function exceeds pipeline deadline without a timeout",
+			deadline:    10 * time.Millisecond,
+			nextDelay:   10000 * time.Millisecond,
+			expectError: "operation timed out: pipeline deadline exceeded",
+		},
+		{
+			name:        "errors within timeout are returned",
+			timeout:     time.Second,
+			nextErr:     errors.New("failed"),
+			expectError: "failed",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			timeout := &Timeout{timer: time.NewTimer(tt.timeout)}
+			timeout := &Timeout{timeout: tt.timeout}
 			pipeline := &mockPipeline{}
 			nextCalled := false
 
 			next := func(ctx StepContext) (StepContext, error) {
-				if tt.nextDelay > 0 {
-					time.Sleep(tt.nextDelay)
-				}
 				nextCalled = true
 
-				return ctx, nil
+				select {
+				case <-ctx.Done():
+					return ctx, ctx.Err()
+				case <-time.After(tt.nextDelay):
+					return ctx, tt.nextErr
+				}
 			}
 
 			nextFunc, err := timeout.Bootstrap(pipeline, next)
@@ -106,16 +113,22 @@ func TestTimeoutBootstrap(t *testing.T) {
 			require.NotNil(t, nextFunc)
 
 			ctx := StepContext{Context: context.Background()}
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx.Context, cancel = context.WithTimeout(ctx.Context, tt.deadline)
+				defer cancel()
+			}
+
 			resultCtx, resultErr := nextFunc(ctx)
+			assert.True(t, nextCalled)
+			assert.Equal(t, ctx, resultCtx)
 
-			if tt.expectError {
-				assert.False(t, nextCalled)
-				assert.Error(t, resultErr)
-				assert.Contains(t, resultErr.Error(), "operation timed out")
+			if tt.expectError != "" {
+				require.Error(t, resultErr)
+				assert.Equal(t, tt.expectError, resultErr.Error())
+				assert.Equal(t, tt.nextErr == nil, errors.Is(resultErr, ErrTimeout))
 			} else {
-				assert.True(t, nextCalled)
 				assert.NoError(t, resultErr)
-				assert.Equal(t, ctx, resultCtx)
 			}
 		})
 	}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"regexp"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+var aliasPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// IsValidAlias reports whether the name can be used as short name for an installed pipeline.
+// Aliases can't contain any characters used by other reference types like '/', ':', '.' or '@'.
+func IsValidAlias(name string) bool {
+	return aliasPattern.MatchString(name)
+}
+
+type AliasLookup func(alias string) (string, bool)
+
+type aliasProvider struct {
+	provider Interface
+	lookup   AliasLookup
+}
+
+// WithAliases expands aliases to the full pipeline reference before resolving it.
+// A file in the current directory with the same name takes precedence over an alias.
+func WithAliases(provider Interface, lookup AliasLookup) Interface {
+	return &aliasProvider{
+		provider: provider,
+		lookup:   lookup,
+	}
+}
+
+func (p *aliasProvider) Resolve(ctx context.Context, ref string) (v1beta1.Pipeline, error) {
+	return p.provider.Resolve(ctx, p.expand(ref))
+}
+
+func (p *aliasProvider) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	return p.provider.Fetch(ctx, p.expand(ref))
+}
+
+func (p *aliasProvider) expand(ref string) string {
+	if !IsValidAlias(ref) {
+		return ref
+	}
+
+	if _, err := os.Stat(ref); err == nil {
+		return ref
+	}
+
+	if name, ok := p.lookup(ref); ok {
+		return name
+	}
+
+	return ref
+}
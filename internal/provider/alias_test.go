@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockProvider struct {
+	refs []string
+}
+
+func (m *mockProvider) Resolve(ctx context.Context, ref string) (v1beta1.Pipeline, error) {
+	m.refs = append(m.refs, ref)
+	return v1beta1.Pipeline{}, nil
+}
+
+func (m *mockProvider) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	m.refs = append(m.refs, ref)
+	return nil, nil
+}
+
+func TestIsValidAlias(t *testing.T) {
+	assert.True(t, IsValidAlias("build"))
+	assert.True(t, IsValidAlias("build-and_test2"))
+	assert.False(t, IsValidAlias(""))
+	assert.False(t, IsValidAlias("-build"))
+	assert.False(t, IsValidAlias("ghcr.io/org/build:v1"))
+	assert.False(t, IsValidAlias("./build"))
+	assert.False(t, IsValidAlias("build@^1.0"))
+}
+
+func TestWithAliases(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	aliases := map[string]string{
+		"build": "ghcr.io/org/build:v1",
+	}
+
+	mock := &mockProvider{}
+	p := WithAliases(mock, func(alias string) (string, bool) {
+		name, ok := aliases[alias]
+		return name, ok
+	})
+
+	for _, ref := range []string{"build", "test", "ghcr.io/org/test:v1", ""} {
+		_, err := p.Resolve(context.Background(), ref)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"ghcr.io/org/build:v1", "test", "ghcr.io/org/test:v1", ""}, mock.refs)
+}
@@ -16,13 +16,61 @@ import (
 type Database struct {
 	store   v1beta1.Store
 	encoder kruntime.Serializer
+	removed []string
 	mu      sync.RWMutex
 }
 
+type AppOption func(app *v1beta1.App)
+
+func WithSource(source string) AppOption {
+	return func(app *v1beta1.App) {
+		app.Source = source
+	}
+}
+
+func WithRevision(revision string) AppOption {
+	return func(app *v1beta1.App) {
+		app.Revision = revision
+	}
+}
+
+func WithETag(etag string) AppOption {
+	return func(app *v1beta1.App) {
+		app.ETag = etag
+	}
+}
+
+func WithDigest(digest string) AppOption {
+	return func(app *v1beta1.App) {
+		app.Digest = digest
+	}
+}
+
+func WithTag(tag string) AppOption {
+	return func(app *v1beta1.App) {
+		app.Tag = tag
+	}
+}
+
+func WithVerified(verified bool) AppOption {
+	return func(app *v1beta1.App) {
+		app.Verified = verified
+	}
+}
+
 type dbGetter interface {
 	Get(name string) (v1beta1.App, error)
 }
 
+type dbAdder interface {
+	Add(name string, manifest []byte, opts ...AppOption) error
+}
+
+type dbStore interface {
+	dbGetter
+	dbAdder
+}
+
 func WithLocalDB(db dbGetter) Resolver {
 	return func(ctx context.Context, ref string) (io.Reader, error) {
 		app, err := db.Get(ref)
@@ -76,27 +124,79 @@ func (d *Database) Remove(name string) error {
 		return cmp.Name == name
 	})
 
+	d.removed = append(d.removed, name)
 	return nil
 }
 
-func (d *Database) Add(name string, manifest []byte) error {
+func (d *Database) Add(name string, manifest []byte, opts ...AppOption) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	d.store.Apps = slices.DeleteFunc(d.store.Apps, func(cmp v1beta1.App) bool {
-		return cmp.Name == name
-	})
-
 	app := v1beta1.App{
 		Name:        name,
 		InstalledAt: metav1.Now(),
 		Manifest:    manifest,
 	}
 
+	// An alias is assigned by the user and survives updates of the pipeline itself.
+	for _, existing := range d.store.Apps {
+		if existing.Name == name {
+			app.Alias = existing.Alias
+		}
+	}
+
+	d.store.Apps = slices.DeleteFunc(d.store.Apps, func(cmp v1beta1.App) bool {
+		return cmp.Name == name
+	})
+
+	d.removed = slices.DeleteFunc(d.removed, func(cmp string) bool {
+		return cmp == name
+	})
+
+	for _, opt := range opts {
+		opt(&app)
+	}
+
 	d.store.Apps = append(d.store.Apps, app)
 	return nil
 }
 
+// SetAlias assigns a short name to an installed pipeline.
+// An alias can only point to one pipeline, it is removed from any other pipeline.
+func (d *Database) SetAlias(name, alias string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.has(name) {
+		return fmt.Errorf("no such pipeline found in local db store: %q", name)
+	}
+
+	for i, app := range d.store.Apps {
+		switch {
+		case app.Name == name:
+			d.store.Apps[i].Alias = alias
+		case alias != "" && app.Alias == alias:
+			d.store.Apps[i].Alias = ""
+		}
+	}
+
+	return nil
+}
+
+// Lookup returns the pipeline name for the given alias.
+func (d *Database) Lookup(alias string) (string, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, app := range d.store.Apps {
+		if app.Alias != "" && app.Alias == alias {
+			return app.Name, true
+		}
+	}
+
+	return "", false
+}
+
 func (d *Database) has(name string) bool {
 	for _, app := range d.store.Apps {
 		if app.Name == name {
@@ -107,6 +207,13 @@ func (d *Database) has(name string) bool {
 	return false
 }
 
+func (d *Database) List() []v1beta1.App {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return slices.Clone(d.store.Apps)
+}
+
 func (d *Database) Get(name string) (v1beta1.App, error) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
@@ -131,6 +238,12 @@ func (d *Database) Merge(from *Database) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	for _, name := range from.removed {
+		d.store.Apps = slices.DeleteFunc(d.store.Apps, func(cmp v1beta1.App) bool {
+			return cmp.Name == name
+		})
+	}
+
 	for _, fromApp := range from.store.Apps {
 		has := false
 		for i, app := range d.store.Apps {
@@ -421,6 +421,83 @@ func TestDatabase_Persist(t *testing.T) {
 		})
 	}
 }
+func TestDatabase_Merge(t *testing.T) {
+	encoder := createTestEncoder()
+	to := &Database{
+		store: v1beta1.Store{
+			StoreSpec: v1beta1.StoreSpec{
+				Apps: []v1beta1.App{
+					{Name: "app1", Manifest: []byte("manifest1")},
+					{Name: "app2", Manifest: []byte("manifest2")},
+				},
+			},
+		},
+		encoder: encoder,
+	}
+
+	from := &Database{
+		store: v1beta1.Store{
+			StoreSpec: v1beta1.StoreSpec{
+				Apps: []v1beta1.App{
+					{Name: "app1", Manifest: []byte("manifest1")},
+					{Name: "app2", Manifest: []byte("manifest2")},
+				},
+			},
+		},
+		encoder: encoder,
+	}
+
+	require.NoError(t, from.Remove("app1"))
+	require.NoError(t, from.Add("app2", []byte("updated")))
+	require.NoError(t, from.Add("app3", []byte("manifest3")))
+	require.NoError(t, to.Merge(from))
+
+	var names []string
+	for _, app := range to.List() {
+		names = append(names, app.Name)
+	}
+
+	assert.Equal(t, []string{"app2", "app3"}, names)
+	app, err := to.Get("app2")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("updated"), app.Manifest)
+}
+
+func TestDatabase_SetAlias(t *testing.T) {
+	db := &Database{
+		store: v1beta1.Store{
+			StoreSpec: v1beta1.StoreSpec{
+				Apps: []v1beta1.App{
+					{Name: "ghcr.io/org/build:v1", Manifest: []byte("manifest1")},
+					{Name: "ghcr.io/org/test:v1", Manifest: []byte("manifest2")},
+				},
+			},
+		},
+		encoder: createTestEncoder(),
+	}
+
+	require.Error(t, db.SetAlias("does-not-exist", "build"))
+	require.NoError(t, db.SetAlias("ghcr.io/org/test:v1", "build"))
+	require.NoError(t, db.SetAlias("ghcr.io/org/build:v1", "build"))
+
+	name, ok := db.Lookup("build")
+	assert.True(t, ok)
+	assert.Equal(t, "ghcr.io/org/build:v1", name)
+
+	app, err := db.Get("ghcr.io/org/test:v1")
+	require.NoError(t, err)
+	assert.Empty(t, app.Alias)
+
+	// The alias is kept if the pipeline gets updated
+	require.NoError(t, db.Add("ghcr.io/org/build:v1", []byte("updated")))
+	name, ok = db.Lookup("build")
+	assert.True(t, ok)
+	assert.Equal(t, "ghcr.io/org/build:v1", name)
+
+	_, ok = db.Lookup("")
+	assert.False(t, ok)
+}
+
 func TestWithLocalDB(t *testing.T) {
 	tests := []struct {
 		name        string
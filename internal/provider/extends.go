@@ -0,0 +1,223 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+const maxExtendsDepth = 10
+
+type extendsProvider struct {
+	provider Interface
+}
+
+// WithExtends merges resolved pipelines with the pipeline they extend.
+// Fetch returns the manifest as is, without the extended pipeline being merged.
+func WithExtends(provider Interface) Interface {
+	return &extendsProvider{
+		provider: provider,
+	}
+}
+
+func (p *extendsProvider) Resolve(ctx context.Context, ref string) (v1beta1.Pipeline, error) {
+	pipeline, err := p.provider.Resolve(ctx, ref)
+	if err != nil {
+		return pipeline, err
+	}
+
+	return p.extend(ctx, pipeline, []string{ref})
+}
+
+func (p *extendsProvider) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	return p.provider.Fetch(ctx, ref)
+}
+
+func (p *extendsProvider) extend(ctx context.Context, pipeline v1beta1.Pipeline, refs []string) (v1beta1.Pipeline, error) {
+	if pipeline.Extends == "" {
+		return ApplyStepPatches(pipeline)
+	}
+
+	if slices.Contains(refs, pipeline.Extends) {
+		return pipeline, fmt.Errorf("circular extends detected: pipeline %q is already extended", pipeline.Extends)
+	}
+
+	if len(refs) > maxExtendsDepth {
+		return pipeline, fmt.Errorf("pipeline extends exceed the max depth of %d", maxExtendsDepth)
+	}
+
+	base, err := p.provider.Resolve(ctx, pipeline.Extends)
+	if err != nil {
+		return pipeline, fmt.Errorf("failed to resolve extended pipeline %q: %w", pipeline.Extends, err)
+	}
+
+	base, err = p.extend(ctx, base, append(refs, pipeline.Extends))
+	if err != nil {
+		return pipeline, err
+	}
+
+	merged, err := MergePipeline(base, pipeline)
+	if err != nil {
+		return pipeline, fmt.Errorf("failed to extend pipeline %q: %w", pipeline.Extends, err)
+	}
+
+	return ApplyStepPatches(merged)
+}
+
+// MergePipeline merges the overlay into the base pipeline.
+// Inputs, outputs and steps are matched by name, overlay steps are merged into the base step using a JSON merge patch.
+// Steps, inputs and outputs which do not exist in the base pipeline are appended.
+func MergePipeline(base, overlay v1beta1.Pipeline) (v1beta1.Pipeline, error) {
+	result := *base.DeepCopy()
+	result.TypeMeta = overlay.TypeMeta
+	result.Extends = ""
+	result.Patches = overlay.Patches
+
+	if overlay.Name != "" {
+		result.ObjectMeta = *overlay.ObjectMeta.DeepCopy()
+	}
+
+	if overlay.Entrypoint != "" {
+		result.Entrypoint = overlay.Entrypoint
+	}
+
+	if overlay.ShortDescription != "" {
+		result.ShortDescription = overlay.ShortDescription
+	}
+
+	if overlay.LongDescription != "" {
+		result.LongDescription = overlay.LongDescription
+	}
+
+	result.Inputs = mergeNamed(result.Inputs, overlay.Inputs, func(input v1beta1.InputParam) string {
+		return input.Name
+	})
+
+	result.Outputs = mergeNamed(result.Outputs, overlay.Outputs, func(output v1beta1.OutputParam) string {
+		return output.Name
+	})
+
+	for _, step := range overlay.Steps {
+		i := slices.IndexFunc(result.Steps, func(s v1beta1.Step) bool {
+			return s.Name == step.Name
+		})
+
+		if i == -1 {
+			result.Steps = append(result.Steps, step)
+			continue
+		}
+
+		merged, err := mergeStep(result.Steps[i], step)
+		if err != nil {
+			return result, err
+		}
+
+		result.Steps[i] = merged
+	}
+
+	return result, nil
+}
+
+// ApplyStepPatches applies the JSON patches of the pipeline to the named steps.
+func ApplyStepPatches(pipeline v1beta1.Pipeline) (v1beta1.Pipeline, error) {
+	for _, patch := range pipeline.Patches {
+		i := slices.IndexFunc(pipeline.Steps, func(s v1beta1.Step) bool {
+			return s.Name == patch.Step.Name
+		})
+
+		if i == -1 {
+			return pipeline, fmt.Errorf("patch references unknown step %q", patch.Step.Name)
+		}
+
+		operations, err := json.Marshal(patch.Patch)
+		if err != nil {
+			return pipeline, fmt.Errorf("failed to marshal patch for step %q: %w", patch.Step.Name, err)
+		}
+
+		decoded, err := jsonpatch.DecodePatch(operations)
+		if err != nil {
+			return pipeline, fmt.Errorf("invalid patch for step %q: %w", patch.Step.Name, err)
+		}
+
+		step, err := json.Marshal(pipeline.Steps[i])
+		if err != nil {
+			return pipeline, fmt.Errorf("failed to marshal step %q: %w", patch.Step.Name, err)
+		}
+
+		patched, err := decoded.Apply(step)
+		if err != nil {
+			return pipeline, fmt.Errorf("failed to patch step %q: %w", patch.Step.Name, err)
+		}
+
+		var patchedStep v1beta1.Step
+		if err := json.Unmarshal(patched, &patchedStep); err != nil {
+			return pipeline, fmt.Errorf("failed to unmarshal patched step %q: %w", patch.Step.Name, err)
+		}
+
+		pipeline.Steps[i] = patchedStep
+	}
+
+	pipeline.Patches = nil
+	return pipeline, nil
+}
+
+func mergeStep(base, overlay v1beta1.Step) (v1beta1.Step, error) {
+	original, err := json.Marshal(base)
+	if err != nil {
+		return base, fmt.Errorf("failed to marshal step %q: %w", base.Name, err)
+	}
+
+	b, err := json.Marshal(overlay)
+	if err != nil {
+		return base, fmt.Errorf("failed to marshal step %q: %w", overlay.Name, err)
+	}
+
+	var patch map[string]any
+	if err := json.Unmarshal(b, &patch); err != nil {
+		return base, fmt.Errorf("failed to unmarshal step %q: %w", overlay.Name, err)
+	}
+
+	// The timeout is always encoded, an unset timeout must not reset the one of the base step
+	if overlay.Timeout.Duration == 0 {
+		delete(patch, "timeout")
+	}
+
+	b, err = json.Marshal(patch)
+	if err != nil {
+		return base, fmt.Errorf("failed to marshal step %q: %w", overlay.Name, err)
+	}
+
+	merged, err := jsonpatch.MergePatch(original, b)
+	if err != nil {
+		return base, fmt.Errorf("failed to merge step %q: %w", overlay.Name, err)
+	}
+
+	var mergedStep v1beta1.Step
+	if err := json.Unmarshal(merged, &mergedStep); err != nil {
+		return base, fmt.Errorf("failed to unmarshal merged step %q: %w", overlay.Name, err)
+	}
+
+	return mergedStep, nil
+}
+
+func mergeNamed[S ~[]T, T any](base, overlay S, name func(T) string) S {
+	result := slices.Clone(base)
+
+	for _, item := range overlay {
+		i := slices.IndexFunc(result, func(existing T) bool {
+			return name(existing) == name(item)
+		})
+
+		if i == -1 {
+			result = append(result, item)
+		} else {
+			result[i] = item
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,168 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+type mockPipelineProvider struct {
+	pipelines map[string]string
+}
+
+func (m *mockPipelineProvider) Resolve(ctx context.Context, ref string) (v1beta1.Pipeline, error) {
+	var pipeline v1beta1.Pipeline
+	manifest, ok := m.pipelines[ref]
+	if !ok {
+		return pipeline, errors.New("not found")
+	}
+
+	err := yaml.Unmarshal([]byte(manifest), &pipeline)
+	return pipeline, err
+}
+
+func (m *mockPipelineProvider) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	manifest, ok := m.pipelines[ref]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+
+	return []byte(manifest), nil
+}
+
+const upstreamPipeline = `
+metadata:
+  name: upstream
+entrypoint: main
+inputs:
+- name: version
+- name: race
+steps:
+- name: main
+  and:
+    refs:
+    - name: lint
+    - name: test
+- name: lint
+  timeout: 5m
+  run:
+    image: golangci/golangci-lint
+    args: [run]
+- name: test
+  run:
+    image: golang:$(context.inputs.version)
+    script: go test ./...
+`
+
+func TestWithExtends(t *testing.T) {
+	store := WithExtends(&mockPipelineProvider{
+		pipelines: map[string]string{
+			"ghcr.io/org/upstream:v1": upstreamPipeline,
+			"overlay.yaml": `
+metadata:
+  name: overlay
+extends: ghcr.io/org/upstream:v1
+inputs:
+- name: version
+  default: "1.25"
+- name: extra
+steps:
+- name: lint
+  run:
+    args: [run, --fast]
+- name: docs
+  run:
+    image: alpine
+patches:
+- step:
+    name: main
+  patch:
+  - op: add
+    path: /and/refs/-
+    value:
+      name: docs
+`,
+		},
+	})
+
+	pipeline, err := store.Resolve(context.Background(), "overlay.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, "overlay", pipeline.Name)
+	assert.Equal(t, "main", pipeline.Entrypoint)
+	assert.Empty(t, pipeline.Extends)
+	assert.Empty(t, pipeline.Patches)
+
+	var inputs []string
+	for _, input := range pipeline.Inputs {
+		inputs = append(inputs, input.Name)
+	}
+	assert.Equal(t, []string{"version", "race", "extra"}, inputs)
+	assert.Equal(t, "1.25", pipeline.Inputs[0].Default.StringVal)
+
+	var steps []string
+	for _, step := range pipeline.Steps {
+		steps = append(steps, step.Name)
+	}
+	assert.Equal(t, []string{"main", "lint", "test", "docs"}, steps)
+
+	lint := pipeline.Steps[1]
+	assert.Equal(t, "golangci/golangci-lint", lint.Run.Image)
+	assert.Equal(t, []string{"run", "--fast"}, lint.Run.Args)
+	assert.Equal(t, metav1.Duration{Duration: 5 * time.Minute}, lint.Timeout)
+
+	assert.Equal(t, []v1beta1.StepReference{{Name: "lint"}, {Name: "test"}, {Name: "docs"}}, pipeline.Steps[0].And.Refs)
+}
+
+func TestWithExtends_Errors(t *testing.T) {
+	store := WithExtends(&mockPipelineProvider{
+		pipelines: map[string]string{
+			"a.yaml":       "extends: b.yaml\nsteps:\n- name: a",
+			"b.yaml":       "extends: a.yaml\nsteps:\n- name: b",
+			"missing.yaml": "extends: does-not-exist.yaml",
+			"patch.yaml":   "steps:\n- name: a\npatches:\n- step:\n    name: b",
+		},
+	})
+
+	_, err := store.Resolve(context.Background(), "a.yaml")
+	assert.ErrorContains(t, err, "circular extends detected")
+
+	_, err = store.Resolve(context.Background(), "missing.yaml")
+	assert.ErrorContains(t, err, `failed to resolve extended pipeline "does-not-exist.yaml"`)
+
+	_, err = store.Resolve(context.Background(), "patch.yaml")
+	assert.ErrorContains(t, err, `patch references unknown step "b"`)
+}
+
+func TestApplyStepPatches(t *testing.T) {
+	pipeline := v1beta1.Pipeline{
+		PipelineSpec: v1beta1.PipelineSpec{
+			Steps: []v1beta1.Step{
+				{Name: "a", Run: &v1beta1.RunStep{Container: v1beta1.Container{Image: "alpine"}}},
+			},
+			Patches: []v1beta1.StepPatch{
+				{
+					Step: v1beta1.StepReference{Name: "a"},
+					Patch: []v1beta1.JSONPatchOperation{
+						{Op: "replace", Path: "/run/image", Value: &runtime.RawExtension{Raw: []byte(`"busybox"`)}},
+						{Op: "add", Path: "/allowFailure", Value: &runtime.RawExtension{Raw: []byte(`true`)}},
+					},
+				},
+			},
+		},
+	}
+
+	patched, err := ApplyStepPatches(pipeline)
+	require.NoError(t, err)
+	assert.Equal(t, "busybox", patched.Steps[0].Run.Image)
+	assert.True(t, patched.Steps[0].AllowFailure.Always)
+	assert.Nil(t, patched.Patches)
+}
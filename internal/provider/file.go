@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -9,6 +10,20 @@ import (
 
 func WithFile() Resolver {
 	return func(ctx context.Context, ref string) (io.Reader, error) {
+		if isStarlarkFile(ref) {
+			src, err := os.ReadFile(ref)
+			if err != nil {
+				return nil, fmt.Errorf("file: failed to open file: %w", err)
+			}
+
+			manifest, err := EvalStarlark(ctx, ref, src, StarlarkInputsFromContext(ctx))
+			if err != nil {
+				return nil, fmt.Errorf("file: %w", err)
+			}
+
+			return bytes.NewReader(manifest), nil
+		}
+
 		r, err := os.Open(ref)
 		if err != nil {
 			return nil, fmt.Errorf("file: failed to open file: %w", err)
@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/gofrs/flock"
+)
+
+const gitRefPrefix = "git+"
+
+var gitRefSpecs = []config.RefSpec{
+	"+refs/heads/*:refs/heads/*",
+	"+refs/tags/*:refs/tags/*",
+}
+
+type gitRef struct {
+	url      string
+	path     string
+	revision string
+}
+
+// IsGitRef returns true if the reference is resolved from a git repository.
+func IsGitRef(ref string) bool {
+	return strings.HasPrefix(ref, gitRefPrefix)
+}
+
+// parseGitRef parses references in the format git+<url>[//<path>][@<revision>].
+func parseGitRef(ref string) (gitRef, error) {
+	if !IsGitRef(ref) {
+		return gitRef{}, errors.New("git: no git reference")
+	}
+
+	ref = strings.TrimPrefix(ref, gitRefPrefix)
+	schemeEnd := strings.Index(ref, "://")
+	if schemeEnd == -1 {
+		return gitRef{}, fmt.Errorf("git: missing scheme in reference %q", ref)
+	}
+
+	scheme := ref[:schemeEnd+3]
+	rest := ref[schemeEnd+3:]
+	result := gitRef{
+		path: RagetaFile,
+	}
+
+	// The revision is only looked up after the host part, otherwise a user in an url (git@github.com) would match.
+	if pathStart := strings.Index(rest, "/"); pathStart != -1 {
+		if i := strings.LastIndex(rest, "@"); i > pathStart {
+			result.revision = rest[i+1:]
+			rest = rest[:i]
+		}
+	}
+
+	// file:///path has an empty host, the sub path separator must be looked up after the leading slash.
+	offset := 0
+	if strings.HasPrefix(rest, "/") {
+		offset = 1
+	}
+
+	if i := strings.Index(rest[offset:], "//"); i != -1 {
+		result.path = rest[offset+i+2:]
+		rest = rest[:offset+i]
+	}
+
+	if rest == "" || result.path == "" {
+		return gitRef{}, fmt.Errorf("git: invalid reference %q", ref)
+	}
+
+	result.url = scheme + rest
+	return result, nil
+}
+
+func WithGit(cacheDir string, db dbAdder) Resolver {
+	return func(ctx context.Context, ref string) (io.Reader, error) {
+		gitRef, err := parseGitRef(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		repoDir := filepath.Join(cacheDir, fmt.Sprintf("%x", sha256.Sum256([]byte(gitRef.url))))
+		if err := os.MkdirAll(cacheDir, 0750); err != nil {
+			return nil, fmt.Errorf("git: failed to create cache directory: %w", err)
+		}
+
+		fileLock := flock.New(fmt.Sprintf("%s.lock", repoDir))
+		if err := fileLock.Lock(); err != nil {
+			return nil, fmt.Errorf("git: failed to lock repository cache: %w", err)
+		}
+		defer func() { _ = fileLock.Unlock() }()
+
+		repo, err := syncGitRepository(ctx, repoDir, gitRef.url)
+		if err != nil {
+			return nil, err
+		}
+
+		revision := gitRef.revision
+		if revision == "" {
+			revision = plumbing.HEAD.String()
+		}
+
+		hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+		if err != nil {
+			return nil, fmt.Errorf("git: failed to resolve revision %q: %w", revision, err)
+		}
+
+		commit, err := repo.CommitObject(*hash)
+		if err != nil {
+			return nil, fmt.Errorf("git: failed to lookup commit %s: %w", hash, err)
+		}
+
+		manifestPath := gitRef.path
+		file, err := commit.File(manifestPath)
+		if err != nil && manifestPath == RagetaFile {
+			// Repositories may define the pipeline in starlark instead
+			if starFile, starErr := commit.File(RagetaStarFile); starErr == nil {
+				file, err = starFile, nil
+				manifestPath = RagetaStarFile
+			}
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("git: failed to open manifest %q: %w", gitRef.path, err)
+		}
+
+		contents, err := file.Contents()
+		if err != nil {
+			return nil, fmt.Errorf("git: failed to read manifest %q: %w", manifestPath, err)
+		}
+
+		manifest := []byte(contents)
+		if isStarlarkFile(manifestPath) {
+			manifest, err = EvalStarlark(ctx, manifestPath, manifest, StarlarkInputsFromContext(ctx))
+			if err != nil {
+				return nil, fmt.Errorf("git: %w", err)
+			}
+		}
+
+		if err := db.Add(ref, manifest, WithSource(gitRef.url), WithRevision(fmt.Sprintf("%s@sha1:%s", revision, hash))); err != nil {
+			return nil, fmt.Errorf("git: failed to add pipeline to local db: %w", err)
+		}
+
+		return bytes.NewReader(manifest), nil
+	}
+}
+
+func syncGitRepository(ctx context.Context, repoDir, url string) (*git.Repository, error) {
+	repo, err := git.PlainOpen(repoDir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		repo, err = git.PlainCloneContext(ctx, repoDir, true, &git.CloneOptions{
+			URL: url,
+		})
+
+		if err != nil {
+			_ = os.RemoveAll(repoDir)
+			return nil, fmt.Errorf("git: failed to clone repository: %w", err)
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("git: failed to open repository cache: %w", err)
+	}
+
+	err = repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		RefSpecs:   gitRefSpecs,
+		Tags:       git.AllTags,
+		Force:      true,
+	})
+
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil, fmt.Errorf("git: failed to fetch repository: %w", err)
+	}
+
+	return repo, nil
+}
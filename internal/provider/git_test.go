@@ -0,0 +1,263 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/raffis/rageta/pkg/apis/package/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDBAdder struct {
+	apps map[string]mockDBApp
+}
+
+type mockDBApp struct {
+	manifest []byte
+	opts     []AppOption
+}
+
+func (m *mockDBAdder) Add(name string, manifest []byte, opts ...AppOption) error {
+	if m.apps == nil {
+		m.apps = make(map[string]mockDBApp)
+	}
+
+	m.apps[name] = mockDBApp{manifest: manifest, opts: opts}
+	return nil
+}
+
+func applyAppOptions(opts []AppOption) v1beta1.App {
+	app := v1beta1.App{}
+	for _, opt := range opts {
+		opt(&app)
+	}
+
+	return app
+}
+
+func createBareRepository(t *testing.T, commits ...map[string]string) (string, []string) {
+	t.Helper()
+
+	workDir := t.TempDir()
+	repo, err := git.PlainInit(workDir, false)
+	require.NoError(t, err)
+
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	var hashes []string
+	for i, files := range commits {
+		for path, content := range files {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(workDir, path)), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(workDir, path), []byte(content), 0644))
+			_, err := worktree.Add(path)
+			require.NoError(t, err)
+		}
+
+		hash, err := worktree.Commit("commit", &git.CommitOptions{
+			Author: &object.Signature{Name: "rageta", Email: "rageta@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+
+		_, err = repo.CreateTag(fmt.Sprintf("v1.0.%d", i), hash, nil)
+		require.NoError(t, err)
+		hashes = append(hashes, hash.String())
+	}
+
+	bareDir := filepath.Join(t.TempDir(), "repo.git")
+	_, err = git.PlainClone(bareDir, true, &git.CloneOptions{URL: workDir})
+	require.NoError(t, err)
+
+	return bareDir, hashes
+}
+
+func TestParseGitRef(t *testing.T) {
+	tests := []struct {
+		name        string
+		ref         string
+		expected    gitRef
+		expectError bool
+	}{
+		{
+			name: "file url with path and revision",
+			ref:  "git+file:///path/repo.git//pipelines/build.yaml@v1.2.0",
+			expected: gitRef{
+				url:      "file:///path/repo.git",
+				path:     "pipelines/build.yaml",
+				revision: "v1.2.0",
+			},
+		},
+		{
+			name: "https url without path and revision",
+			ref:  "git+https://github.com/org/repo.git",
+			expected: gitRef{
+				url:  "https://github.com/org/repo.git",
+				path: RagetaFile,
+			},
+		},
+		{
+			name: "ssh url with user",
+			ref:  "git+ssh://git@github.com/org/repo.git//rageta.yaml@main",
+			expected: gitRef{
+				url:      "ssh://git@github.com/org/repo.git",
+				path:     "rageta.yaml",
+				revision: "main",
+			},
+		},
+		{
+			name: "ssh url with user and without revision",
+			ref:  "git+ssh://git@github.com/org/repo.git",
+			expected: gitRef{
+				url:  "ssh://git@github.com/org/repo.git",
+				path: RagetaFile,
+			},
+		},
+		{
+			name:        "no git prefix",
+			ref:         "ghcr.io/org/pipeline:v1",
+			expectError: true,
+		},
+		{
+			name:        "missing scheme",
+			ref:         "git+/path/repo.git",
+			expectError: true,
+		},
+		{
+			name:        "empty sub path",
+			ref:         "git+file:///path/repo.git//",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := parseGitRef(tt.ref)
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, ref)
+		})
+	}
+}
+
+func TestWithGit(t *testing.T) {
+	repoDir, hashes := createBareRepository(t,
+		map[string]string{"pipelines/build.yaml": "v1", "rageta.yaml": "root v1"},
+		map[string]string{"pipelines/build.yaml": "v2"},
+	)
+
+	tests := []struct {
+		name             string
+		ref              string
+		expectError      bool
+		expectedContent  string
+		expectedRevision string
+	}{
+		{
+			name:             "tag with sub path",
+			ref:              "git+file://" + repoDir + "//pipelines/build.yaml@v1.0.0",
+			expectedContent:  "v1",
+			expectedRevision: "v1.0.0@sha1:" + hashes[0],
+		},
+		{
+			name:             "default branch with sub path",
+			ref:              "git+file://" + repoDir + "//pipelines/build.yaml",
+			expectedContent:  "v2",
+			expectedRevision: "HEAD@sha1:" + hashes[1],
+		},
+		{
+			name:             "commit sha with default manifest",
+			ref:              "git+file://" + repoDir + "@" + hashes[0],
+			expectedContent:  "root v1",
+			expectedRevision: hashes[0] + "@sha1:" + hashes[0],
+		},
+		{
+			name:        "unknown revision",
+			ref:         "git+file://" + repoDir + "//pipelines/build.yaml@v9.9.9",
+			expectError: true,
+		},
+		{
+			name:        "unknown sub path",
+			ref:         "git+file://" + repoDir + "//does-not-exist.yaml",
+			expectError: true,
+		},
+		{
+			name:        "not a git ref",
+			ref:         repoDir,
+			expectError: true,
+		},
+	}
+
+	cacheDir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &mockDBAdder{}
+			reader, err := WithGit(cacheDir, db)(context.Background(), tt.ref)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Nil(t, reader)
+				assert.Empty(t, db.apps)
+				return
+			}
+
+			require.NoError(t, err)
+			data, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedContent, string(data))
+
+			require.Contains(t, db.apps, tt.ref)
+			assert.Equal(t, tt.expectedContent, string(db.apps[tt.ref].manifest))
+
+			app := applyAppOptions(db.apps[tt.ref].opts)
+			assert.Equal(t, "file://"+repoDir, app.Source)
+			assert.Equal(t, tt.expectedRevision, app.Revision)
+		})
+	}
+}
+
+func TestWithGit_FetchesNewCommits(t *testing.T) {
+	workDir := t.TempDir()
+	repo, err := git.PlainInit(workDir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	commit := func(content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, RagetaFile), []byte(content), 0644))
+		_, err := worktree.Add(RagetaFile)
+		require.NoError(t, err)
+		_, err = worktree.Commit(content, &git.CommitOptions{
+			Author: &object.Signature{Name: "rageta", Email: "rageta@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+	}
+
+	commit("first")
+	cacheDir := t.TempDir()
+	ref := "git+file://" + workDir
+
+	reader, err := WithGit(cacheDir, &mockDBAdder{})(context.Background(), ref)
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(data))
+
+	commit("second")
+	reader, err = WithGit(cacheDir, &mockDBAdder{})(context.Background(), ref)
+	require.NoError(t, err)
+	data, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+}
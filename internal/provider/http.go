@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const sha256Pin = "sha256="
+
+// IsHTTPRef returns true if the reference is fetched from an http url.
+func IsHTTPRef(ref string) bool {
+	return strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://")
+}
+
+func WithHTTP(client *http.Client, db dbStore) Resolver {
+	return func(ctx context.Context, ref string) (io.Reader, error) {
+		if !IsHTTPRef(ref) {
+			return nil, errors.New("http: no http reference")
+		}
+
+		u, err := url.Parse(ref)
+		if err != nil {
+			return nil, fmt.Errorf("http: invalid reference: %w", err)
+		}
+
+		var checksum string
+		if u.Fragment != "" {
+			if !strings.HasPrefix(u.Fragment, sha256Pin) {
+				return nil, fmt.Errorf("http: unsupported fragment %q, only %s<hex> is supported", u.Fragment, sha256Pin)
+			}
+
+			checksum = strings.ToLower(strings.TrimPrefix(u.Fragment, sha256Pin))
+			u.Fragment = ""
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("http: failed to create request: %w", err)
+		}
+
+		cached, err := db.Get(ref)
+		if err == nil && cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+
+		res, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("http: request failed: %w", err)
+		}
+
+		defer func() {
+			_ = res.Body.Close()
+		}()
+
+		var manifest []byte
+		etag := res.Header.Get("ETag")
+
+		switch {
+		case res.StatusCode == http.StatusNotModified && cached.Manifest != nil:
+			manifest = cached.Manifest
+			etag = cached.ETag
+		case res.StatusCode == http.StatusOK:
+			manifest, err = io.ReadAll(res.Body)
+			if err != nil {
+				return nil, fmt.Errorf("http: failed to read response body: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("http: unexpected status code %d", res.StatusCode)
+		}
+
+		sum := sha256.Sum256(manifest)
+		digest := hex.EncodeToString(sum[:])
+		if checksum != "" && checksum != digest {
+			return nil, fmt.Errorf("http: checksum mismatch, expected sha256:%s but got sha256:%s", checksum, digest)
+		}
+
+		if err := db.Add(ref, manifest, WithSource(u.String()), WithRevision("sha256:"+digest), WithETag(etag)); err != nil {
+			return nil, fmt.Errorf("http: failed to add pipeline to local db: %w", err)
+		}
+
+		return bytes.NewReader(manifest), nil
+	}
+}
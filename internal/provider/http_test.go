@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHTTP(t *testing.T) {
+	manifest := []byte("kind: Pipeline")
+	sum := sha256.Sum256(manifest)
+	digest := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pipeline.yaml":
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			_, _ = w.Write(manifest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		ref         string
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name: "successful request",
+			ref:  server.URL + "/pipeline.yaml",
+		},
+		{
+			name: "matching checksum",
+			ref:  server.URL + "/pipeline.yaml#sha256=" + digest,
+		},
+		{
+			name:        "checksum mismatch",
+			ref:         server.URL + "/pipeline.yaml#sha256=0000",
+			expectError: true,
+			errorMsg:    "checksum mismatch",
+		},
+		{
+			name:        "unsupported fragment",
+			ref:         server.URL + "/pipeline.yaml#md5=0000",
+			expectError: true,
+			errorMsg:    "unsupported fragment",
+		},
+		{
+			name:        "not found",
+			ref:         server.URL + "/does-not-exist.yaml",
+			expectError: true,
+			errorMsg:    "unexpected status code 404",
+		},
+		{
+			name:        "no http ref",
+			ref:         "ghcr.io/org/pipeline:v1",
+			expectError: true,
+			errorMsg:    "no http reference",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &Database{encoder: createTestEncoder()}
+			reader, err := WithHTTP(server.Client(), db)(context.Background(), tt.ref)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+				assert.Nil(t, reader)
+				assert.Empty(t, db.store.Apps)
+				return
+			}
+
+			require.NoError(t, err)
+			data, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, manifest, data)
+
+			app, err := db.Get(tt.ref)
+			require.NoError(t, err)
+			assert.Equal(t, server.URL+"/pipeline.yaml", app.Source)
+			assert.Equal(t, "sha256:"+digest, app.Revision)
+			assert.Equal(t, `"v1"`, app.ETag)
+		})
+	}
+}
+
+func TestWithHTTP_ETagCache(t *testing.T) {
+	var requests, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		_, _ = w.Write([]byte("kind: Pipeline"))
+	}))
+	defer server.Close()
+
+	db := &Database{encoder: createTestEncoder()}
+	ref := server.URL + "/pipeline.yaml"
+
+	for i := 0; i < 2; i++ {
+		reader, err := WithHTTP(server.Client(), db)(context.Background(), ref)
+		require.NoError(t, err)
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "kind: Pipeline", string(data))
+	}
+
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, 1, notModified)
+}
+
+func TestWithHTTP_CachedChecksumMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	ref := server.URL + "/pipeline.yaml#sha256=0000"
+	db := &Database{encoder: createTestEncoder()}
+	require.NoError(t, db.Add(ref, []byte("tampered"), WithETag(`"v1"`)))
+
+	_, err := WithHTTP(server.Client(), db)(context.Background(), ref)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "checksum mismatch")
+}
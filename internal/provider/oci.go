@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -14,7 +15,42 @@ type ociPuller interface {
 	Pull(context.Context, string, string, ...oci.PullOption) (*oci.Metadata, error)
 }
 
-func WithOCI(ociClient ociPuller) Resolver {
+type ociVerifier interface {
+	Verify(ctx context.Context, digest string) error
+}
+
+type ociOptions struct {
+	verifier  ociVerifier
+	tagLister tagLister
+	db        dbStore
+}
+
+type OCIOption func(o *ociOptions)
+
+func WithOCIVerifier(verifier ociVerifier) OCIOption {
+	return func(o *ociOptions) {
+		o.verifier = verifier
+	}
+}
+
+func WithOCITagLister(lister tagLister) OCIOption {
+	return func(o *ociOptions) {
+		o.tagLister = lister
+	}
+}
+
+func WithOCIDatabase(db dbStore) OCIOption {
+	return func(o *ociOptions) {
+		o.db = db
+	}
+}
+
+func WithOCI(ociClient ociPuller, opts ...OCIOption) Resolver {
+	o := &ociOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	return func(ctx context.Context, ref string) (io.Reader, error) {
 		tmp, err := os.MkdirTemp("", "rageta")
 		if err != nil {
@@ -24,16 +60,94 @@ func WithOCI(ociClient ociPuller) Resolver {
 			_ = os.RemoveAll(tmp)
 		}()
 
-		_, err = ociClient.Pull(ctx, ref, tmp)
+		pullRef, tag, err := o.resolveVersion(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+
+		meta, err := ociClient.Pull(ctx, pullRef, tmp)
 		if err != nil {
 			return nil, fmt.Errorf("oci: failed to pull image: %w", err)
 		}
 
-		r, err := os.Open(filepath.Join(tmp, "main.yaml"))
+		if meta == nil {
+			meta = &oci.Metadata{}
+		}
+
+		verified, err := o.verify(ctx, ref, meta.Digest)
 		if err != nil {
-			return nil, fmt.Errorf("oci: failed to open manifest: %w", err)
+			return nil, err
 		}
 
-		return r, nil
+		manifest, err := readOCIManifest(ctx, tmp)
+		if err != nil {
+			return nil, err
+		}
+
+		if o.db == nil {
+			return bytes.NewReader(manifest), nil
+		}
+
+		if err := o.db.Add(ref, manifest, WithSource(meta.Source), WithRevision(meta.Revision), WithDigest(meta.Digest), WithVerified(verified), WithTag(tag)); err != nil {
+			return nil, fmt.Errorf("oci: failed to add pipeline to local db: %w", err)
+		}
+
+		return bytes.NewReader(manifest), nil
+	}
+}
+
+func (o *ociOptions) resolveVersion(ctx context.Context, ref string) (string, string, error) {
+	versionRef, ok := ParseVersionRef(ref)
+	if !ok {
+		return ref, "", nil
+	}
+
+	if o.tagLister == nil {
+		return "", "", fmt.Errorf("oci: version range references are not supported: %q", ref)
+	}
+
+	tag, err := versionRef.Resolve(ctx, o.tagLister)
+	if err != nil {
+		return "", "", fmt.Errorf("oci: failed to resolve version range: %w", err)
+	}
+
+	return fmt.Sprintf("%s:%s", versionRef.Repository, tag), tag, nil
+}
+
+func (o *ociOptions) verify(ctx context.Context, ref, digest string) (bool, error) {
+	if o.verifier == nil {
+		return false, nil
+	}
+
+	if o.db != nil {
+		if app, err := o.db.Get(ref); err == nil && app.Verified && app.Digest == digest {
+			return true, nil
+		}
+	}
+
+	if err := o.verifier.Verify(ctx, digest); err != nil {
+		return false, fmt.Errorf("oci: signature verification failed: %w", err)
 	}
+
+	return true, nil
+}
+
+// readOCIManifest reads the pipeline from a pulled artifact, either main.yaml or the starlark definition main.star.
+func readOCIManifest(ctx context.Context, dir string) ([]byte, error) {
+	manifest, err := os.ReadFile(filepath.Join(dir, "main.yaml"))
+	if err == nil {
+		return manifest, nil
+	}
+
+	src, starErr := os.ReadFile(filepath.Join(dir, "main.star"))
+	if starErr != nil {
+		return nil, fmt.Errorf("oci: failed to open manifest: %w", err)
+	}
+
+	manifest, err = EvalStarlark(ctx, "main.star", src, StarlarkInputsFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("oci: %w", err)
+	}
+
+	return manifest, nil
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/fluxcd/pkg/oci"
+	"github.com/raffis/rageta/pkg/apis/package/v1beta1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -161,3 +162,92 @@ func TestWithOCI(t *testing.T) {
 		})
 	}
 }
+
+type mockOCIVerifier struct {
+	calls int
+	err   error
+}
+
+func (m *mockOCIVerifier) Verify(ctx context.Context, digest string) error {
+	m.calls++
+	return m.err
+}
+
+func TestWithOCI_Verify(t *testing.T) {
+	digest := "example.com/image@sha256:abc"
+	puller := &mockOCIPuller{
+		pullFunc: func(ctx context.Context, ref string, path string, opts ...oci.PullOption) (*oci.Metadata, error) {
+			err := os.WriteFile(filepath.Join(path, "main.yaml"), []byte("test manifest"), 0644)
+			if err != nil {
+				return nil, err
+			}
+			return &oci.Metadata{Digest: digest}, nil
+		},
+	}
+
+	tests := []struct {
+		name             string
+		verifier         *mockOCIVerifier
+		cached           *v1beta1.App
+		expectError      bool
+		expectedCalls    int
+		expectedVerified bool
+	}{
+		{
+			name:             "verified artifact is recorded in db",
+			verifier:         &mockOCIVerifier{},
+			expectedCalls:    1,
+			expectedVerified: true,
+		},
+		{
+			name:          "verification failure",
+			verifier:      &mockOCIVerifier{err: assert.AnError},
+			expectError:   true,
+			expectedCalls: 1,
+		},
+		{
+			name:             "cached verified digest skips verification",
+			verifier:         &mockOCIVerifier{err: assert.AnError},
+			cached:           &v1beta1.App{Digest: digest, Verified: true},
+			expectedCalls:    0,
+			expectedVerified: true,
+		},
+		{
+			name:             "cached verified digest differs from pulled digest",
+			verifier:         &mockOCIVerifier{},
+			cached:           &v1beta1.App{Digest: "example.com/image@sha256:old", Verified: true},
+			expectedCalls:    1,
+			expectedVerified: true,
+		},
+	}
+
+	ref := "example.com/image:tag"
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &Database{encoder: createTestEncoder()}
+			if tt.cached != nil {
+				require.NoError(t, db.Add(ref, []byte("cached"), WithDigest(tt.cached.Digest), WithVerified(tt.cached.Verified)))
+			}
+
+			reader, err := WithOCI(puller, WithOCIVerifier(tt.verifier), WithOCIDatabase(db))(context.Background(), ref)
+			assert.Equal(t, tt.expectedCalls, tt.verifier.calls)
+
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "signature verification failed")
+				return
+			}
+
+			require.NoError(t, err)
+			data, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, "test manifest", string(data))
+
+			app, err := db.Get(ref)
+			require.NoError(t, err)
+			assert.Equal(t, digest, app.Digest)
+			assert.Equal(t, tt.expectedVerified, app.Verified)
+			assert.Equal(t, []byte("test manifest"), app.Manifest)
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 
 type Interface interface {
 	Resolve(ctx context.Context, ref string) (v1beta1.Pipeline, error)
+	Fetch(ctx context.Context, ref string) ([]byte, error)
 }
 
 type provider struct {
@@ -30,28 +31,34 @@ func New(decoder runtime.Decoder, handlers ...Resolver) *provider {
 
 func (s *provider) Resolve(ctx context.Context, ref string) (v1beta1.Pipeline, error) {
 	to := v1beta1.Pipeline{}
+	manifest, err := s.Fetch(ctx, ref)
+	if err != nil {
+		return to, err
+	}
+
+	_, _, err = s.decoder.Decode(
+		manifest,
+		nil,
+		&to)
+
+	if err != nil {
+		return to, err
+	}
+
+	return to, nil
+}
+
+// Fetch returns the raw manifest from the first resolver which is able to handle the reference.
+func (s *provider) Fetch(ctx context.Context, ref string) ([]byte, error) {
 	var errs []error
 
 	for _, handler := range s.handlers {
 		if r, err := handler(ctx, ref); err == nil {
-			manifest, err := io.ReadAll(r)
-			if err != nil {
-				return to, err
-			}
-			_, _, err = s.decoder.Decode(
-				manifest,
-				nil,
-				&to)
-
-			if err != nil {
-				return to, err
-			}
-
-			return to, nil
+			return io.ReadAll(r)
 		} else {
 			errs = append(errs, err)
 		}
 	}
 
-	return to, fmt.Errorf("could not lookup ref: %q: %w", ref, errors.Join(errs...))
+	return nil, fmt.Errorf("could not lookup ref: %q: %w", ref, errors.Join(errs...))
 }
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -17,10 +18,21 @@ func WithRagetafile() Resolver {
 		}
 
 		r, err := os.Open(RagetaFile)
-		if err != nil {
+		if err == nil {
+			return r, nil
+		}
+
+		// A starlark definition is only used if there is no yaml manifest
+		src, starErr := os.ReadFile(RagetaStarFile)
+		if starErr != nil {
 			return nil, fmt.Errorf("ragetafile: failed to open file: %w", err)
 		}
 
-		return r, nil
+		manifest, err := EvalStarlark(ctx, RagetaStarFile, src, StarlarkInputsFromContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("ragetafile: %w", err)
+		}
+
+		return bytes.NewReader(manifest), nil
 	}
 }
@@ -0,0 +1,103 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/fluxcd/pkg/oci"
+	"github.com/fluxcd/pkg/version"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+type tagLister interface {
+	ListTags(ctx context.Context, repository string) ([]string, error)
+}
+
+// VersionRef is an OCI reference pinned to a semver range instead of an exact tag,
+// e.g. ghcr.io/org/pipeline@^1.4.
+type VersionRef struct {
+	Repository string
+	Constraint *semver.Constraints
+}
+
+func ParseVersionRef(ref string) (VersionRef, bool) {
+	i := strings.LastIndex(ref, "@")
+	if i == -1 || strings.Contains(ref[i+1:], ":") {
+		return VersionRef{}, false
+	}
+
+	constraint, err := semver.NewConstraint(ref[i+1:])
+	if err != nil {
+		return VersionRef{}, false
+	}
+
+	if _, err := name.NewRepository(ref[:i]); err != nil {
+		return VersionRef{}, false
+	}
+
+	return VersionRef{
+		Repository: ref[:i],
+		Constraint: constraint,
+	}, true
+}
+
+// Latest returns the highest tag matching the constraint.
+func (r VersionRef) Latest(tags []string) (string, error) {
+	var (
+		latest    *semver.Version
+		latestTag string
+	)
+
+	for _, tag := range tags {
+		if oci.IsCosignArtifact(tag) {
+			continue
+		}
+
+		v, err := version.ParseVersion(tag)
+		if err != nil || !r.Constraint.Check(v) {
+			continue
+		}
+
+		if latest == nil || v.GreaterThan(latest) {
+			latest = v
+			latestTag = tag
+		}
+	}
+
+	if latest == nil {
+		return "", fmt.Errorf("no tag matching %q found in %s", r.Constraint, r.Repository)
+	}
+
+	return latestTag, nil
+}
+
+func (r VersionRef) Resolve(ctx context.Context, lister tagLister) (string, error) {
+	tags, err := lister.ListTags(ctx, r.Repository)
+	if err != nil {
+		return "", fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	return r.Latest(tags)
+}
+
+type registryTagLister struct {
+	opts []remote.Option
+}
+
+func NewRegistryTagLister(opts ...remote.Option) *registryTagLister {
+	return &registryTagLister{
+		opts: opts,
+	}
+}
+
+func (l *registryTagLister) ListTags(ctx context.Context, repository string) ([]string, error) {
+	repo, err := name.NewRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	return remote.List(repo, append(l.opts, remote.WithContext(ctx))...)
+}
@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fluxcd/pkg/oci"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTagLister struct {
+	tags []string
+	err  error
+}
+
+func (m *mockTagLister) ListTags(ctx context.Context, repository string) ([]string, error) {
+	return m.tags, m.err
+}
+
+func TestParseVersionRef(t *testing.T) {
+	tests := []struct {
+		name               string
+		ref                string
+		expectOk           bool
+		expectedRepository string
+	}{
+		{
+			name:               "caret range",
+			ref:                "ghcr.io/org/pipe@^1.4",
+			expectOk:           true,
+			expectedRepository: "ghcr.io/org/pipe",
+		},
+		{
+			name:               "tilde range",
+			ref:                "ghcr.io/org/pipe@~2.0",
+			expectOk:           true,
+			expectedRepository: "ghcr.io/org/pipe",
+		},
+		{
+			name:               "comparison range",
+			ref:                "ghcr.io/org/pipe@>=1.0.0 <2.0.0",
+			expectOk:           true,
+			expectedRepository: "ghcr.io/org/pipe",
+		},
+		{
+			name: "digest",
+			ref:  "ghcr.io/org/pipe@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		},
+		{
+			name: "exact tag",
+			ref:  "ghcr.io/org/pipe:v1.4.0",
+		},
+		{
+			name: "invalid constraint",
+			ref:  "ghcr.io/org/pipe@latest",
+		},
+		{
+			name: "git reference",
+			ref:  "git+ssh://git@github.com/org/repo.git@main",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versionRef, ok := ParseVersionRef(tt.ref)
+			assert.Equal(t, tt.expectOk, ok)
+			assert.Equal(t, tt.expectedRepository, versionRef.Repository)
+		})
+	}
+}
+
+func TestVersionRef_Latest(t *testing.T) {
+	tags := []string{"v1.3.0", "v1.4.0", "v1.4.2", "1.5.0", "v2.0.0", "v2.0.1-rc.1", "latest", "sha256-abc.sig"}
+
+	tests := []struct {
+		name        string
+		ref         string
+		expected    string
+		expectError bool
+	}{
+		{name: "caret", ref: "ghcr.io/org/pipe@^1.4", expected: "1.5.0"},
+		{name: "tilde", ref: "ghcr.io/org/pipe@~1.4", expected: "v1.4.2"},
+		{name: "major", ref: "ghcr.io/org/pipe@~2.0", expected: "v2.0.0"},
+		{name: "no match", ref: "ghcr.io/org/pipe@^3.0", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versionRef, ok := ParseVersionRef(tt.ref)
+			require.True(t, ok)
+
+			tag, err := versionRef.Latest(tags)
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, tag)
+		})
+	}
+}
+
+func TestRegistryTagLister_ListTags(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	repository := fmt.Sprintf("%s/org/pipeline", u.Host)
+	for _, tag := range []string{"v1.0.0", "v1.1.0"} {
+		img, err := random.Image(64, 1)
+		require.NoError(t, err)
+		ref, err := name.ParseReference(repository + ":" + tag)
+		require.NoError(t, err)
+		require.NoError(t, remote.Write(ref, img))
+	}
+
+	tags, err := NewRegistryTagLister().ListTags(context.Background(), repository)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"v1.0.0", "v1.1.0"}, tags)
+}
+
+func TestWithOCI_VersionRange(t *testing.T) {
+	var pulled string
+	puller := &mockOCIPuller{
+		pullFunc: func(ctx context.Context, ref string, path string, opts ...oci.PullOption) (*oci.Metadata, error) {
+			pulled = ref
+			err := os.WriteFile(filepath.Join(path, "main.yaml"), []byte("test manifest"), 0644)
+			if err != nil {
+				return nil, err
+			}
+			return &oci.Metadata{Digest: "ghcr.io/org/pipe@sha256:abc"}, nil
+		},
+	}
+
+	ref := "ghcr.io/org/pipe@^1.4"
+
+	t.Run("resolves highest matching tag", func(t *testing.T) {
+		db := &Database{encoder: createTestEncoder()}
+		lister := &mockTagLister{tags: []string{"v1.4.0", "v1.4.3", "v2.0.0"}}
+
+		_, err := WithOCI(puller, WithOCITagLister(lister), WithOCIDatabase(db))(context.Background(), ref)
+		require.NoError(t, err)
+		assert.Equal(t, "ghcr.io/org/pipe:v1.4.3", pulled)
+
+		app, err := db.Get(ref)
+		require.NoError(t, err)
+		assert.Equal(t, "v1.4.3", app.Tag)
+		assert.Equal(t, "ghcr.io/org/pipe@sha256:abc", app.Digest)
+	})
+
+	t.Run("no matching tag", func(t *testing.T) {
+		lister := &mockTagLister{tags: []string{"v2.0.0"}}
+		_, err := WithOCI(puller, WithOCITagLister(lister))(context.Background(), ref)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to resolve version range")
+	})
+
+	t.Run("without tag lister", func(t *testing.T) {
+		_, err := WithOCI(puller)(context.Background(), ref)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "version range references are not supported")
+	})
+}
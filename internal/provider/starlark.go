@@ -0,0 +1,406 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+const RagetaStarFile = "rageta.star"
+
+// Upper bound of computation steps, a script is aborted once it is reached.
+const starlarkMaxExecutionSteps = 10_000_000
+
+func isStarlarkFile(path string) bool {
+	return filepath.Ext(path) == ".star"
+}
+
+type starlarkInputsKey struct{}
+
+// WithStarlarkInputs returns a context carrying the inputs passed to starlark pipeline definitions resolved with it.
+func WithStarlarkInputs(ctx context.Context, inputs map[string]string) context.Context {
+	return context.WithValue(ctx, starlarkInputsKey{}, inputs)
+}
+
+func StarlarkInputsFromContext(ctx context.Context) map[string]string {
+	inputs, _ := ctx.Value(starlarkInputsKey{}).(map[string]string)
+	return inputs
+}
+
+// EvalStarlark evaluates a starlark pipeline definition and returns the resulting pipeline manifest.
+// The script runs sandboxed, it has neither access to the filesystem nor the network and load() is not supported.
+// Besides the starlark language the builtins pipeline(), step(), run(), concurrent() and matrix() are available.
+// The given inputs are available as the read-only dict inputs, they are the only values passed to the script.
+func EvalStarlark(ctx context.Context, filename string, src []byte, inputs map[string]string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("starlark: %w", err)
+	}
+
+	builder := &starlarkPipeline{
+		names: make(map[string]bool),
+	}
+
+	thread := &starlark.Thread{
+		Name:  filename,
+		Print: func(_ *starlark.Thread, _ string) {},
+	}
+	thread.SetMaxExecutionSteps(starlarkMaxExecutionSteps)
+
+	stop := context.AfterFunc(ctx, func() {
+		thread.Cancel(ctx.Err().Error())
+	})
+	defer stop()
+
+	// Inputs are added in order as iterating the dict must be deterministic
+	names := make([]string, 0, len(inputs))
+	for name := range inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	inputsDict := starlark.NewDict(len(inputs))
+	for _, name := range names {
+		if err := inputsDict.SetKey(starlark.String(name), starlark.String(inputs[name])); err != nil {
+			return nil, fmt.Errorf("starlark: %w", err)
+		}
+	}
+	inputsDict.Freeze()
+
+	predeclared := starlark.StringDict{
+		"inputs":     inputsDict,
+		"pipeline":   starlark.NewBuiltin("pipeline", builder.pipeline),
+		"step":       starlark.NewBuiltin("step", builder.step),
+		"run":        starlark.NewBuiltin("run", starlarkRun),
+		"concurrent": starlark.NewBuiltin("concurrent", starlarkConcurrent),
+		"matrix":     starlark.NewBuiltin("matrix", starlarkMatrix),
+	}
+
+	opts := &syntax.FileOptions{
+		TopLevelControl: true,
+		GlobalReassign:  true,
+	}
+
+	if _, err := starlark.ExecFileOptions(opts, thread, filename, src, predeclared); err != nil {
+		var evalErr *starlark.EvalError
+		if errors.As(err, &evalErr) {
+			return nil, fmt.Errorf("starlark: %s", evalErr.Backtrace())
+		}
+
+		return nil, fmt.Errorf("starlark: %w", err)
+	}
+
+	return builder.manifest()
+}
+
+type starlarkPipeline struct {
+	spec  map[string]any
+	steps []any
+	names map[string]bool
+}
+
+// pipeline sets the pipeline wide fields, it may only be called once.
+func (p *starlarkPipeline) pipeline(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if len(args) > 0 {
+		return nil, fmt.Errorf("%s: unexpected positional arguments", b.Name())
+	}
+
+	if p.spec != nil {
+		return nil, fmt.Errorf("%s: pipeline already defined", b.Name())
+	}
+
+	spec, err := kwargsToMap(kwargs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+
+	p.spec = spec
+	return starlark.None, nil
+}
+
+// step adds a step to the pipeline and returns its name which can be used to reference it from other steps.
+func (p *starlarkPipeline) step(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	step, err := kwargsToMap(kwargs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+
+	switch {
+	case len(args) == 1:
+		name, ok := starlark.AsString(args[0])
+		if !ok {
+			return nil, fmt.Errorf("%s: name must be a string, got %s", b.Name(), args[0].Type())
+		}
+		step["name"] = name
+	case len(args) > 1:
+		return nil, fmt.Errorf("%s: got %d positional arguments, want at most 1", b.Name(), len(args))
+	}
+
+	name, _ := step["name"].(string)
+	if name == "" {
+		return nil, fmt.Errorf("%s: missing step name", b.Name())
+	}
+
+	if p.names[name] {
+		return nil, fmt.Errorf("%s: step %q already defined", b.Name(), name)
+	}
+
+	normalizeStep(step)
+	p.names[name] = true
+	p.steps = append(p.steps, step)
+
+	return starlark.String(name), nil
+}
+
+func (p *starlarkPipeline) manifest() ([]byte, error) {
+	if len(p.steps) == 0 {
+		return nil, errors.New("starlark: no steps defined")
+	}
+
+	manifest := map[string]any{}
+	for k, v := range p.spec {
+		manifest[k] = v
+	}
+
+	metadata := map[string]any{}
+	if name, ok := manifest["name"]; ok {
+		metadata["name"] = name
+		delete(manifest, "name")
+	}
+
+	manifest["apiVersion"] = v1beta1.GroupVersion.String()
+	manifest["kind"] = "Pipeline"
+	manifest["metadata"] = metadata
+	manifest["steps"] = p.steps
+
+	b, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("starlark: failed to encode pipeline: %w", err)
+	}
+
+	// Unknown fields are most likely typos in keyword arguments which would otherwise be dropped silently
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&v1beta1.Pipeline{}); err != nil {
+		return nil, fmt.Errorf("starlark: invalid pipeline: %w", err)
+	}
+
+	return b, nil
+}
+
+// run returns a run step spec, the image can be passed as first positional argument.
+func starlarkRun(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if len(args) > 1 {
+		return nil, fmt.Errorf("%s: got %d positional arguments, want at most 1", b.Name(), len(args))
+	}
+
+	if len(args) == 1 {
+		kwargs = append([]starlark.Tuple{{starlark.String("image"), args[0]}}, kwargs...)
+	}
+
+	return kwargsToDict(kwargs)
+}
+
+// concurrent returns a concurrent step spec referencing the steps passed as positional arguments.
+func starlarkConcurrent(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	spec, err := kwargsToDict(kwargs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+
+	if err := spec.SetKey(starlark.String("refs"), starlark.NewList(args)); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+var starlarkMatrixOptions = map[string]bool{
+	"failFast":      true,
+	"maxConcurrent": true,
+	"include":       true,
+}
+
+// matrix returns a matrix spec, each keyword argument is a matrix param except the matrix options failFast, maxConcurrent and include.
+func starlarkMatrix(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if len(args) > 0 {
+		return nil, fmt.Errorf("%s: unexpected positional arguments", b.Name())
+	}
+
+	spec := starlark.NewDict(len(kwargs))
+	var params []starlark.Value
+
+	for _, kwarg := range kwargs {
+		name := string(kwarg[0].(starlark.String))
+		if starlarkMatrixOptions[name] {
+			if err := spec.SetKey(kwarg[0], kwarg[1]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		param := starlark.NewDict(2)
+		_ = param.SetKey(starlark.String("name"), kwarg[0])
+		_ = param.SetKey(starlark.String("value"), kwarg[1])
+		params = append(params, param)
+	}
+
+	if err := spec.SetKey(starlark.String("params"), starlark.NewList(params)); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+}
+
+// normalizeStep expands the shorthands supported by step() to the pipeline api.
+func normalizeStep(step map[string]any) {
+	for _, field := range []string{"and", "pipe", "concurrent"} {
+		switch v := step[field].(type) {
+		case []any:
+			step[field] = map[string]any{"refs": stepRefs(v)}
+		case map[string]any:
+			if refs, ok := v["refs"].([]any); ok {
+				v["refs"] = stepRefs(refs)
+			}
+		}
+	}
+
+	switch v := step["needs"].(type) {
+	case string:
+		step["needs"] = stepRefs([]any{v})
+	case []any:
+		step["needs"] = stepRefs(v)
+	}
+
+	if v, ok := step["extends"].(string); ok {
+		step["extends"] = map[string]any{"name": v}
+	}
+
+	if v, ok := step["inherit"].(string); ok {
+		step["inherit"] = map[string]any{"pipeline": v}
+	}
+
+	switch v := step["if"].(type) {
+	case string:
+		step["if"] = []any{map[string]any{"celExpression": v}}
+	case []any:
+		for i, condition := range v {
+			if expr, ok := condition.(string); ok {
+				v[i] = map[string]any{"celExpression": expr}
+			}
+		}
+	}
+
+	for _, field := range []string{"env", "secrets"} {
+		if v, ok := step[field].(map[string]any); ok {
+			step[field] = namedValues(v)
+		}
+	}
+}
+
+func stepRefs(refs []any) []any {
+	for i, ref := range refs {
+		if name, ok := ref.(string); ok {
+			refs[i] = map[string]any{"name": name}
+		}
+	}
+
+	return refs
+}
+
+func namedValues(values map[string]any) []any {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]any, 0, len(names))
+	for _, name := range names {
+		list = append(list, map[string]any{"name": name, "value": values[name]})
+	}
+
+	return list
+}
+
+func kwargsToDict(kwargs []starlark.Tuple) (*starlark.Dict, error) {
+	dict := starlark.NewDict(len(kwargs))
+	for _, kwarg := range kwargs {
+		if err := dict.SetKey(kwarg[0], kwarg[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	return dict, nil
+}
+
+// kwargsToMap converts keyword arguments to a map, a trailing underscore is stripped from the names
+// so fields named like starlark keywords (if, and) can be passed as if_ and and_.
+func kwargsToMap(kwargs []starlark.Tuple) (map[string]any, error) {
+	result := make(map[string]any, len(kwargs))
+	for _, kwarg := range kwargs {
+		name := strings.TrimSuffix(string(kwarg[0].(starlark.String)), "_")
+		value, err := starlarkToGo(kwarg[1])
+		if err != nil {
+			return nil, fmt.Errorf("argument %s: %w", name, err)
+		}
+
+		result[name] = value
+	}
+
+	return result, nil
+}
+
+func starlarkToGo(value starlark.Value) (any, error) {
+	switch v := value.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(v), nil
+	case starlark.Int:
+		i, ok := v.Int64()
+		if !ok {
+			return nil, fmt.Errorf("integer %s out of range", v)
+		}
+		return i, nil
+	case starlark.Float:
+		return float64(v), nil
+	case starlark.String:
+		return string(v), nil
+	case starlark.Indexable:
+		list := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			item, err := starlarkToGo(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, nil
+	case *starlark.Dict:
+		dict := make(map[string]any, v.Len())
+		for _, item := range v.Items() {
+			key, ok := starlark.AsString(item[0])
+			if !ok {
+				return nil, fmt.Errorf("dict keys must be strings, got %s", item[0].Type())
+			}
+
+			value, err := starlarkToGo(item[1])
+			if err != nil {
+				return nil, err
+			}
+			dict[key] = value
+		}
+		return dict, nil
+	default:
+		return nil, fmt.Errorf("unsupported value of type %s", value.Type())
+	}
+}
@@ -0,0 +1,180 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const starlarkPipelineSource = `
+pipeline(name = "build", entrypoint = "main", inputs = [{"name": "version"}])
+
+def go(name, script):
+    return step(name, run = run("golang:$(context.inputs.version)", script = script))
+
+lint = go("lint", "go vet ./...")
+steps = [go("test-" + pkg, "go test ./" + pkg) for pkg in ["api", "cli"]]
+
+step("matrix",
+    matrix = matrix(os = ["linux", "darwin"], failFast = True),
+    env = {"GOOS": "$(context.matrix.os)"},
+    if_ = "context.inputs.version != ''",
+    needs = [lint],
+    run = run(image = "golang", args = ["go", "build"]),
+)
+
+step("main", concurrent = concurrent(failFast = True, *([lint] + steps)))
+`
+
+func TestEvalStarlark(t *testing.T) {
+	manifest, err := EvalStarlark(context.Background(), "rageta.star", []byte(starlarkPipelineSource), nil)
+	require.NoError(t, err)
+
+	var pipeline v1beta1.Pipeline
+	require.NoError(t, json.Unmarshal(manifest, &pipeline))
+
+	assert.Equal(t, "core.rageta.io/v1beta1", pipeline.APIVersion)
+	assert.Equal(t, "Pipeline", pipeline.Kind)
+	assert.Equal(t, "build", pipeline.Name)
+	assert.Equal(t, "main", pipeline.Entrypoint)
+	require.Len(t, pipeline.Inputs, 1)
+	assert.Equal(t, "version", pipeline.Inputs[0].Name)
+
+	var names []string
+	for _, step := range pipeline.Steps {
+		names = append(names, step.Name)
+	}
+	assert.Equal(t, []string{"lint", "test-api", "test-cli", "matrix", "main"}, names)
+
+	assert.Equal(t, "golang:$(context.inputs.version)", pipeline.Steps[1].Run.Image)
+	assert.Equal(t, "go test ./api", pipeline.Steps[1].Run.Script)
+
+	matrixStep := pipeline.Steps[3]
+	assert.True(t, matrixStep.Matrix.FailFast)
+	require.Len(t, matrixStep.Matrix.Params, 1)
+	assert.Equal(t, "os", matrixStep.Matrix.Params[0].Name)
+	assert.Equal(t, []string{"linux", "darwin"}, matrixStep.Matrix.Params[0].Value.ArrayVal)
+	assert.Equal(t, "GOOS", matrixStep.Env[0].Name)
+	assert.Equal(t, "context.inputs.version != ''", *matrixStep.If[0].CelExpression)
+	assert.Equal(t, []v1beta1.StepReference{{Name: "lint"}}, matrixStep.Needs)
+
+	main := pipeline.Steps[4]
+	assert.True(t, main.Concurrent.FailFast)
+	assert.Equal(t, []v1beta1.StepReference{{Name: "lint"}, {Name: "test-api"}, {Name: "test-cli"}}, main.Concurrent.Refs)
+
+	again, err := EvalStarlark(context.Background(), "rageta.star", []byte(starlarkPipelineSource), nil)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, again, "evaluation must be deterministic")
+}
+
+func TestEvalStarlark_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		errorMsg string
+	}{
+		{
+			name:     "no steps",
+			src:      `pipeline(name = "empty")`,
+			errorMsg: "no steps defined",
+		},
+		{
+			name:     "duplicate step",
+			src:      "step(\"a\", run = run(\"alpine\"))\nstep(\"a\", run = run(\"alpine\"))",
+			errorMsg: `step "a" already defined`,
+		},
+		{
+			name:     "unknown field",
+			src:      `step("a", run = run("alpine", scrpit = "echo"))`,
+			errorMsg: `unknown field "scrpit"`,
+		},
+		{
+			name:     "load is not supported",
+			src:      `load("other.star", "x")`,
+			errorMsg: "load not implemented",
+		},
+		{
+			name:     "syntax error",
+			src:      `step(`,
+			errorMsg: "rageta.star:1:6",
+		},
+		{
+			name:     "endless loop",
+			src:      "def loop():\n    for i in range(1000000000):\n        pass\nloop()",
+			errorMsg: "too many steps",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := EvalStarlark(context.Background(), "rageta.star", []byte(tt.src), nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
+
+func TestEvalStarlark_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := EvalStarlark(ctx, "rageta.star", []byte(`step("a", run = run("alpine"))`), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "context canceled")
+}
+
+func TestEvalStarlark_Inputs(t *testing.T) {
+	src := `
+for name in inputs:
+    step(name, run = run(inputs[name]))
+`
+
+	manifest, err := EvalStarlark(context.Background(), "rageta.star", []byte(src), map[string]string{
+		"build": "golang",
+		"lint":  "golangci/golangci-lint",
+	})
+	require.NoError(t, err)
+
+	var pipeline v1beta1.Pipeline
+	require.NoError(t, json.Unmarshal(manifest, &pipeline))
+	require.Len(t, pipeline.Steps, 2)
+	assert.Equal(t, "build", pipeline.Steps[0].Name)
+	assert.Equal(t, "golang", pipeline.Steps[0].Run.Image)
+	assert.Equal(t, "lint", pipeline.Steps[1].Name)
+	assert.Equal(t, "golangci/golangci-lint", pipeline.Steps[1].Run.Image)
+
+	_, err = EvalStarlark(context.Background(), "rageta.star", []byte(`inputs["build"] = "alpine"`), map[string]string{"build": "golang"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "frozen")
+}
+
+func TestWithFile_Starlark(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pipeline.star")
+	require.NoError(t, os.WriteFile(path, []byte(`step("a", run = run("alpine"))`), 0644))
+
+	r, err := WithFile()(context.Background(), path)
+	require.NoError(t, err)
+
+	var pipeline v1beta1.Pipeline
+	require.NoError(t, json.NewDecoder(r).Decode(&pipeline))
+	assert.Equal(t, "alpine", pipeline.Steps[0].Run.Image)
+}
+
+func TestWithFile_StarlarkInputs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pipeline.star")
+	require.NoError(t, os.WriteFile(path, []byte(`step("a", run = run(inputs["image"]))`), 0644))
+
+	ctx := WithStarlarkInputs(context.Background(), map[string]string{"image": "golang"})
+	r, err := WithFile()(ctx, path)
+	require.NoError(t, err)
+
+	var pipeline v1beta1.Pipeline
+	require.NoError(t, json.NewDecoder(r).Decode(&pipeline))
+	assert.Equal(t, "golang", pipeline.Steps[0].Run.Image)
+}
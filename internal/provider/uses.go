@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type usesProvider struct {
+	provider Interface
+	decoder  runtime.Decoder
+}
+
+// WithStepPackages inlines the step packages referenced by steps with `uses` into resolved pipelines.
+func WithStepPackages(provider Interface, decoder runtime.Decoder) Interface {
+	return &usesProvider{
+		provider: provider,
+		decoder:  decoder,
+	}
+}
+
+func (p *usesProvider) Resolve(ctx context.Context, ref string) (v1beta1.Pipeline, error) {
+	pipeline, err := p.provider.Resolve(ctx, ref)
+	if err != nil {
+		return pipeline, err
+	}
+
+	for i, step := range pipeline.Steps {
+		if step.Uses == "" {
+			continue
+		}
+
+		inlined, err := p.inline(ctx, step, nil)
+		if err != nil {
+			return pipeline, fmt.Errorf("step %q: %w", step.Name, err)
+		}
+
+		pipeline.Steps[i] = inlined
+	}
+
+	return pipeline, nil
+}
+
+func (p *usesProvider) Fetch(ctx context.Context, ref string) ([]byte, error) {
+	return p.provider.Fetch(ctx, ref)
+}
+
+// fetchStepPackage resolves and decodes the step package.
+func (p *usesProvider) fetchStepPackage(ctx context.Context, ref string) (v1beta1.StepPackage, error) {
+	pkg := v1beta1.StepPackage{}
+	manifest, err := p.provider.Fetch(ctx, ref)
+	if err != nil {
+		return pkg, err
+	}
+
+	if _, _, err := p.decoder.Decode(manifest, nil, &pkg); err != nil {
+		return pkg, fmt.Errorf("failed to decode step package %q: %w", ref, err)
+	}
+
+	return pkg, nil
+}
+
+func (p *usesProvider) inline(ctx context.Context, step v1beta1.Step, refs []string) (v1beta1.Step, error) {
+	if slices.Contains(refs, step.Uses) {
+		return step, fmt.Errorf("circular uses detected: step package %q is already used", step.Uses)
+	}
+
+	if len(refs) > maxExtendsDepth {
+		return step, fmt.Errorf("step package uses exceed the max depth of %d", maxExtendsDepth)
+	}
+
+	pkg, err := p.fetchStepPackage(ctx, step.Uses)
+	if err != nil {
+		return step, fmt.Errorf("failed to resolve step package: %w", err)
+	}
+
+	if err := validateWith(pkg, step.With); err != nil {
+		return step, fmt.Errorf("step package %q: %w", step.Uses, err)
+	}
+
+	body := pkg.Step
+	body.Name = step.Name
+	if body.Uses != "" {
+		body, err = p.inline(ctx, body, append(refs, step.Uses))
+		if err != nil {
+			return step, err
+		}
+	}
+
+	overlay := step
+	overlay.Uses = ""
+	overlay.With = nil
+	overlay.Inputs = nil
+	overlay.Outputs = nil
+
+	inlined, err := mergeStep(body, overlay)
+	if err != nil {
+		return step, err
+	}
+
+	// The package inputs are declared on the step itself, they are populated from the with values at runtime
+	inlined.Inputs = mergeNamed(inlined.Inputs, pkg.Inputs, func(input v1beta1.InputParam) string {
+		return input.Name
+	})
+
+	inlined.Inputs = mergeNamed(inlined.Inputs, step.Inputs, func(input v1beta1.InputParam) string {
+		return input.Name
+	})
+
+	inlined.Outputs = mergeNamed(pkg.Outputs, inlined.Outputs, func(output v1beta1.StepOutputParam) string {
+		return output.Name
+	})
+
+	inlined.Outputs = mergeNamed(inlined.Outputs, step.Outputs, func(output v1beta1.StepOutputParam) string {
+		return output.Name
+	})
+
+	inlined.Uses = step.Uses
+	inlined.With = step.With
+	return inlined, nil
+}
+
+func validateWith(pkg v1beta1.StepPackage, with []v1beta1.Param) error {
+	for _, param := range with {
+		i := slices.IndexFunc(pkg.Inputs, func(input v1beta1.InputParam) bool {
+			return input.Name == param.Name
+		})
+
+		if i == -1 {
+			return fmt.Errorf("unknown input %q", param.Name)
+		}
+
+		input := pkg.Inputs[i]
+		input.SetDefaults()
+		if param.Value.Type != input.Type {
+			return fmt.Errorf("input %q expects type %s but got %s", param.Name, input.Type, param.Value.Type)
+		}
+	}
+
+	for _, input := range pkg.Inputs {
+		if input.Default != nil || input.CelExpression != nil {
+			continue
+		}
+
+		if !slices.ContainsFunc(with, func(param v1beta1.Param) bool {
+			return param.Name == input.Name
+		}) {
+			return fmt.Errorf("missing input %q", input.Name)
+		}
+	}
+
+	return nil
+}
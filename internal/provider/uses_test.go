@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+)
+
+const golangciStepPackage = `
+apiVersion: core.rageta.io/v1beta1
+kind: StepPackage
+metadata:
+  name: golangci
+inputs:
+- name: version
+  default: v2.1
+- name: args
+  type: array
+outputs:
+- name: report
+step:
+  name: golangci
+  timeout: 5m
+  run:
+    image: golangci/golangci-lint:$(context.inputs.version)
+    args: [$(context.inputs.args)]
+`
+
+func stepPackageDecoder() runtime.Decoder {
+	scheme := runtime.NewScheme()
+	_ = v1beta1.AddToScheme(scheme)
+	return serializer.NewCodecFactory(scheme).UniversalDeserializer()
+}
+
+func TestWithStepPackages(t *testing.T) {
+	store := WithStepPackages(&mockPipelineProvider{
+		pipelines: map[string]string{
+			"ghcr.io/org/steps/golangci:v1": golangciStepPackage,
+			"pipeline.yaml": `
+steps:
+- name: lint
+  uses: ghcr.io/org/steps/golangci:v1
+  with:
+  - name: args
+    value: [run, ./...]
+  run:
+    workingDir: /src
+  outputs:
+  - name: coverage
+- name: test
+  run:
+    image: golang
+`,
+		},
+	}, stepPackageDecoder())
+
+	pipeline, err := store.Resolve(context.Background(), "pipeline.yaml")
+	require.NoError(t, err)
+	require.Len(t, pipeline.Steps, 2)
+
+	lint := pipeline.Steps[0]
+	assert.Equal(t, "lint", lint.Name)
+	assert.Equal(t, "golangci/golangci-lint:$(context.inputs.version)", lint.Run.Image)
+	assert.Equal(t, "/src", lint.Run.WorkingDir)
+	assert.Equal(t, "5m0s", lint.Timeout.Duration.String())
+	assert.Equal(t, "ghcr.io/org/steps/golangci:v1", lint.Uses)
+	assert.Equal(t, []string{"run", "./..."}, lint.With[0].Value.ArrayVal)
+
+	var inputs []string
+	for _, input := range lint.Inputs {
+		inputs = append(inputs, input.Name)
+	}
+	assert.Equal(t, []string{"version", "args"}, inputs)
+
+	var outputs []string
+	for _, output := range lint.Outputs {
+		outputs = append(outputs, output.Name)
+	}
+	assert.Equal(t, []string{"report", "coverage"}, outputs)
+
+	assert.Equal(t, "golang", pipeline.Steps[1].Run.Image)
+}
+
+func TestWithStepPackages_Errors(t *testing.T) {
+	store := WithStepPackages(&mockPipelineProvider{
+		pipelines: map[string]string{
+			"golangci.yaml": golangciStepPackage,
+			"a.yaml":        "apiVersion: core.rageta.io/v1beta1\nkind: StepPackage\nstep:\n  uses: b.yaml",
+			"b.yaml":        "apiVersion: core.rageta.io/v1beta1\nkind: StepPackage\nstep:\n  uses: a.yaml",
+			"unknown.yaml":  "steps:\n- name: lint\n  uses: golangci.yaml\n  with:\n  - name: foo\n    value: bar\n  - name: args\n    value: []",
+			"type.yaml":     "steps:\n- name: lint\n  uses: golangci.yaml\n  with:\n  - name: args\n    value: run",
+			"missing.yaml":  "steps:\n- name: lint\n  uses: golangci.yaml",
+			"circular.yaml": "steps:\n- name: lint\n  uses: a.yaml",
+			"notfound.yaml": "steps:\n- name: lint\n  uses: does-not-exist.yaml",
+		},
+	}, stepPackageDecoder())
+
+	tests := []struct {
+		ref      string
+		errorMsg string
+	}{
+		{ref: "unknown.yaml", errorMsg: `unknown input "foo"`},
+		{ref: "type.yaml", errorMsg: `input "args" expects type array but got string`},
+		{ref: "missing.yaml", errorMsg: `missing input "args"`},
+		{ref: "circular.yaml", errorMsg: "circular uses detected"},
+		{ref: "notfound.yaml", errorMsg: "failed to resolve step package"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			_, err := store.Resolve(context.Background(), tt.ref)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
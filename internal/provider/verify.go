@@ -0,0 +1,262 @@
+package provider
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/raffis/rageta/pkg/apis/package/v1beta1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+)
+
+// cosignSignatureAnnotation is the layer annotation cosign stores the base64 encoded signature in.
+const cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+type Signature struct {
+	Payload   []byte
+	Signature []byte
+}
+
+type signatureFetcher interface {
+	Signatures(ctx context.Context, digest string) ([]Signature, error)
+}
+
+type simpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+func LoadTrustPolicy(r io.Reader, decoder kruntime.Decoder) (v1beta1.TrustPolicy, error) {
+	policy := v1beta1.TrustPolicy{}
+	manifest, err := io.ReadAll(r)
+	if err != nil {
+		return policy, fmt.Errorf("failed to read trust policy: %w", err)
+	}
+
+	if _, _, err := decoder.Decode(manifest, nil, &policy); err != nil {
+		return policy, fmt.Errorf("failed to decode trust policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+type TrustPolicyVerifier struct {
+	policy  v1beta1.TrustPolicy
+	fetcher signatureFetcher
+}
+
+func NewTrustPolicyVerifier(policy v1beta1.TrustPolicy, fetcher signatureFetcher) *TrustPolicyVerifier {
+	return &TrustPolicyVerifier{
+		policy:  policy,
+		fetcher: fetcher,
+	}
+}
+
+// Verify validates that at least one signature attached to the given artifact digest
+// was created by one of the public keys trusted for the artifacts repository.
+func (v *TrustPolicyVerifier) Verify(ctx context.Context, digest string) error {
+	ref, err := name.NewDigest(digest)
+	if err != nil {
+		return fmt.Errorf("invalid artifact digest %q: %w", digest, err)
+	}
+
+	repository := ref.Context().Name()
+	keys, err := v.trustedKeys(repository)
+	if err != nil {
+		return err
+	}
+
+	signatures, err := v.fetcher.Signatures(ctx, digest)
+	if err != nil {
+		return fmt.Errorf("failed to fetch signatures: %w", err)
+	}
+
+	var errs []error
+	for _, signature := range signatures {
+		payload := simpleSigningPayload{}
+		if err := json.Unmarshal(signature.Payload, &payload); err != nil {
+			errs = append(errs, fmt.Errorf("invalid signature payload: %w", err))
+			continue
+		}
+
+		if payload.Critical.Image.DockerManifestDigest != ref.DigestStr() {
+			errs = append(errs, fmt.Errorf("signature is for digest %q", payload.Critical.Image.DockerManifestDigest))
+			continue
+		}
+
+		for _, key := range keys {
+			if err := verifySignature(key, signature.Payload, signature.Signature); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			return nil
+		}
+	}
+
+	if len(signatures) == 0 {
+		errs = append(errs, errors.New("no signatures found"))
+	}
+
+	return fmt.Errorf("no valid signature found for %s: %w", digest, errors.Join(errs...))
+}
+
+func (v *TrustPolicyVerifier) trustedKeys(repository string) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for _, rule := range v.policy.Rules {
+		match, err := path.Match(rule.Match, repository)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trust policy match %q: %w", rule.Match, err)
+		}
+
+		if !match {
+			continue
+		}
+
+		for _, publicKey := range rule.PublicKeys {
+			key, err := loadPublicKey(publicKey)
+			if err != nil {
+				return nil, err
+			}
+
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no trusted public keys configured for repository %q", repository)
+	}
+
+	return keys, nil
+}
+
+// loadPublicKey accepts either a path to a PEM encoded public key or the PEM block itself.
+func loadPublicKey(publicKey string) (crypto.PublicKey, error) {
+	b := []byte(publicKey)
+	if !strings.HasPrefix(strings.TrimSpace(publicKey), "-----BEGIN") {
+		var err error
+		b, err = os.ReadFile(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key: %w", err)
+		}
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded public key found in %q", publicKey)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	return key, nil
+}
+
+func verifySignature(key crypto.PublicKey, payload, signature []byte) error {
+	sum := sha256.Sum256(payload)
+
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, sum[:], signature) {
+			return errors.New("invalid ecdsa signature")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], signature); err != nil {
+			return fmt.Errorf("invalid rsa signature: %w", err)
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, payload, signature) {
+			return errors.New("invalid ed25519 signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", key)
+	}
+
+	return nil
+}
+
+type registrySignatureFetcher struct {
+	opts []remote.Option
+}
+
+// NewRegistrySignatureFetcher looks up cosign compatible signatures which are stored
+// as sha256-<digest>.sig tag alongside the artifact.
+func NewRegistrySignatureFetcher(opts ...remote.Option) *registrySignatureFetcher {
+	return &registrySignatureFetcher{
+		opts: opts,
+	}
+}
+
+func (f *registrySignatureFetcher) Signatures(ctx context.Context, digest string) ([]Signature, error) {
+	ref, err := name.NewDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := ref.Context().Tag(strings.Replace(ref.DigestStr(), ":", "-", 1) + ".sig")
+	img, err := remote.Image(tag, append(f.opts, remote.WithContext(ctx))...)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	var signatures []Signature
+	for _, layer := range manifest.Layers {
+		encoded, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+
+		signature, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode signature: %w", err)
+		}
+
+		blob, err := img.LayerByDigest(layer.Digest)
+		if err != nil {
+			return nil, err
+		}
+
+		r, err := blob.Compressed()
+		if err != nil {
+			return nil, err
+		}
+
+		payload, err := io.ReadAll(r)
+		_ = r.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		signatures = append(signatures, Signature{
+			Payload:   payload,
+			Signature: signature,
+		})
+	}
+
+	return signatures, nil
+}
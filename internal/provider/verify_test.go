@@ -0,0 +1,242 @@
+package provider
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/raffis/rageta/pkg/apis/package/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+)
+
+type mockSignatureFetcher struct {
+	signatures []Signature
+	err        error
+}
+
+func (m *mockSignatureFetcher) Signatures(ctx context.Context, digest string) ([]Signature, error) {
+	return m.signatures, m.err
+}
+
+const testDigest = "ghcr.io/org/pipeline@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+func generateKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "cosign.pub")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644))
+
+	return key, path
+}
+
+func sign(t *testing.T, key crypto.Signer, digest string) Signature {
+	t.Helper()
+
+	ref, err := name.NewDigest(digest)
+	require.NoError(t, err)
+
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":%q},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`,
+		ref.Context().Name(), ref.DigestStr()))
+	sum := sha256.Sum256(payload)
+	signature, err := key.Sign(rand.Reader, sum[:], crypto.SHA256)
+	require.NoError(t, err)
+
+	return Signature{
+		Payload:   payload,
+		Signature: signature,
+	}
+}
+
+func TestTrustPolicyVerifier_Verify(t *testing.T) {
+	key, keyPath := generateKey(t)
+	_, otherKeyPath := generateKey(t)
+
+	tests := []struct {
+		name        string
+		rules       []v1beta1.TrustRule
+		fetcher     *mockSignatureFetcher
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:  "valid signature",
+			rules: []v1beta1.TrustRule{{Match: "ghcr.io/org/*", PublicKeys: []string{keyPath}}},
+			fetcher: &mockSignatureFetcher{
+				signatures: []Signature{sign(t, key, testDigest)},
+			},
+		},
+		{
+			name: "valid signature with one of multiple matching keys",
+			rules: []v1beta1.TrustRule{
+				{Match: "ghcr.io/org/*", PublicKeys: []string{otherKeyPath}},
+				{Match: "ghcr.io/*/pipeline", PublicKeys: []string{keyPath}},
+			},
+			fetcher: &mockSignatureFetcher{
+				signatures: []Signature{sign(t, key, testDigest)},
+			},
+		},
+		{
+			name:  "signed by untrusted key",
+			rules: []v1beta1.TrustRule{{Match: "ghcr.io/org/*", PublicKeys: []string{otherKeyPath}}},
+			fetcher: &mockSignatureFetcher{
+				signatures: []Signature{sign(t, key, testDigest)},
+			},
+			expectError: true,
+			errorMsg:    "invalid ecdsa signature",
+		},
+		{
+			name:  "signature for another digest",
+			rules: []v1beta1.TrustRule{{Match: "ghcr.io/org/*", PublicKeys: []string{keyPath}}},
+			fetcher: &mockSignatureFetcher{
+				signatures: []Signature{sign(t, key, strings.Replace(testDigest, "sha256:0", "sha256:1", 1))},
+			},
+			expectError: true,
+			errorMsg:    "signature is for digest",
+		},
+		{
+			name:        "no matching rule",
+			rules:       []v1beta1.TrustRule{{Match: "docker.io/*", PublicKeys: []string{keyPath}}},
+			fetcher:     &mockSignatureFetcher{},
+			expectError: true,
+			errorMsg:    "no trusted public keys configured",
+		},
+		{
+			name:        "no signatures",
+			rules:       []v1beta1.TrustRule{{Match: "ghcr.io/org/*", PublicKeys: []string{keyPath}}},
+			fetcher:     &mockSignatureFetcher{},
+			expectError: true,
+			errorMsg:    "no signatures found",
+		},
+		{
+			name:        "fetch error",
+			rules:       []v1beta1.TrustRule{{Match: "ghcr.io/org/*", PublicKeys: []string{keyPath}}},
+			fetcher:     &mockSignatureFetcher{err: errors.New("registry unavailable")},
+			expectError: true,
+			errorMsg:    "registry unavailable",
+		},
+		{
+			name:        "missing public key file",
+			rules:       []v1beta1.TrustRule{{Match: "ghcr.io/org/*", PublicKeys: []string{"/does/not/exist.pub"}}},
+			fetcher:     &mockSignatureFetcher{},
+			expectError: true,
+			errorMsg:    "failed to read public key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := NewTrustPolicyVerifier(v1beta1.TrustPolicy{
+				TrustPolicySpec: v1beta1.TrustPolicySpec{Rules: tt.rules},
+			}, tt.fetcher)
+
+			err := verifier.Verify(context.Background(), testDigest)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestLoadTrustPolicy(t *testing.T) {
+	scheme := kruntime.NewScheme()
+	require.NoError(t, v1beta1.AddToScheme(scheme))
+	decoder := serializer.NewCodecFactory(scheme).UniversalDeserializer()
+
+	policy, err := LoadTrustPolicy(strings.NewReader(`
+apiVersion: package.rageta.io/v1beta1
+kind: TrustPolicy
+rules:
+- match: ghcr.io/org/*
+  publicKeys:
+  - /keys/cosign.pub
+`), decoder)
+
+	require.NoError(t, err)
+	assert.Equal(t, []v1beta1.TrustRule{{Match: "ghcr.io/org/*", PublicKeys: []string{"/keys/cosign.pub"}}}, policy.Rules)
+}
+
+func TestRegistrySignatureFetcher_Signatures(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+
+	repository := fmt.Sprintf("%s/org/pipeline", u.Host)
+	ref, err := name.ParseReference(repository + ":v1")
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, img))
+
+	hash, err := img.Digest()
+	require.NoError(t, err)
+	digest := fmt.Sprintf("%s@%s", repository, hash)
+
+	key, keyPath := generateKey(t)
+	signature := sign(t, key, digest)
+
+	sigImg, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: static.NewLayer(signature.Payload, types.MediaType("application/vnd.dev.cosign.simplesigning.v1+json")),
+		Annotations: map[string]string{
+			cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature.Signature),
+		},
+	})
+	require.NoError(t, err)
+
+	sigRef, err := name.ParseReference(fmt.Sprintf("%s:%s.sig", repository, strings.Replace(hash.String(), ":", "-", 1)))
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(sigRef, sigImg))
+
+	fetcher := NewRegistrySignatureFetcher()
+	signatures, err := fetcher.Signatures(context.Background(), digest)
+	require.NoError(t, err)
+	require.Len(t, signatures, 1)
+	assert.Equal(t, signature, signatures[0])
+
+	verifier := NewTrustPolicyVerifier(v1beta1.TrustPolicy{
+		TrustPolicySpec: v1beta1.TrustPolicySpec{
+			Rules: []v1beta1.TrustRule{{Match: repository, PublicKeys: []string{keyPath}}},
+		},
+	}, fetcher)
+	require.NoError(t, verifier.Verify(context.Background(), digest))
+
+	_, err = fetcher.Signatures(context.Background(), fmt.Sprintf("%s/org/unsigned@%s", u.Host, hash))
+	require.Error(t, err)
+}
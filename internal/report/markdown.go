@@ -1,6 +1,7 @@
 package report
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -37,6 +38,7 @@ func (r *markdown) Finalize() error {
 		}
 
 		errMsg, status, duration := r.stringify(step.result)
+		status = step.status(status)
 		fmt.Fprintf(r.w, "| %d | %s | %s | %s | %s | %s |\n",
 			i,
 			step.stepName,
@@ -47,6 +49,8 @@ func (r *markdown) Finalize() error {
 		)
 	}
 
+	fmt.Fprintf(r.w, "\n%s\n", r.store.Totals())
+
 	return nil
 }
 
@@ -60,6 +64,12 @@ func (r *markdown) stringify(step processor.StepContext) (string, string, string
 	switch {
 	case step.StartedAt.IsZero():
 		status = `🕙`
+	case errors.Is(step.Error, processor.ErrAllowFailure):
+		status = `🔶`
+		errMsg = strings.ReplaceAll(step.Error.Error(), "\n", "")
+	case errors.Is(step.Error, processor.ErrTimeout):
+		status = `⏱️`
+		errMsg = strings.ReplaceAll(step.Error.Error(), "\n", "")
 	case step.Error != nil && !processor.AbortOnError(step.Error):
 		status = `⚠️`
 		errMsg = strings.ReplaceAll(step.Error.Error(), "\n", "")
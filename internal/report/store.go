@@ -1,10 +1,13 @@
 package report
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/raffis/rageta/internal/processor"
 )
@@ -19,6 +22,62 @@ type store struct {
 	mu    sync.Mutex
 }
 
+func (r stepResult) MarshalJSON() ([]byte, error) {
+	result := struct {
+		Step      string                    `json:"step"`
+		Result    string                    `json:"result"`
+		Error     string                    `json:"error,omitempty"`
+		StartedAt time.Time                 `json:"startedAt"`
+		EndedAt   time.Time                 `json:"endedAt"`
+		Tags      []processor.Tag           `json:"tags,omitempty"`
+		Approval  *processor.ApprovalResult `json:"approval,omitempty"`
+		Case      string                    `json:"case,omitempty"`
+		Attempt   int                       `json:"attempt,omitempty"`
+	}{
+		Step:      r.stepName,
+		Result:    processor.ErrorResult(r.result.Error),
+		StartedAt: r.result.StartedAt,
+		EndedAt:   r.result.EndedAt,
+		Tags:      r.result.Tags.Tags(),
+	}
+
+	if r.result.Error != nil {
+		result.Error = r.result.Error.Error()
+	}
+
+	if approval, ok := r.result.ApprovalOf(r.stepName); ok {
+		result.Approval = &approval
+	}
+
+	if selected, ok := r.result.SwitchOf(r.stepName); ok {
+		result.Case = selected
+	}
+
+	if attempt, ok := r.result.AttemptOf(r.stepName); ok {
+		result.Attempt = attempt
+	}
+
+	return json.Marshal(result)
+}
+
+// status appends the attempt of retried steps, the approval decision of approval steps
+// and the selected case of switch steps to the rendered status.
+func (r stepResult) status(status string) string {
+	if attempt, ok := r.result.AttemptOf(r.stepName); ok {
+		status = fmt.Sprintf("%s #%d", status, attempt)
+	}
+
+	if approval, ok := r.result.ApprovalOf(r.stepName); ok {
+		return fmt.Sprintf("%s %s", status, approval)
+	}
+
+	if selected, ok := r.result.SwitchOf(r.stepName); ok {
+		return fmt.Sprintf("%s case %s", status, selected)
+	}
+
+	return status
+}
+
 func (s *store) Add(stepName string, ctx processor.StepContext) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -28,21 +87,82 @@ func (s *store) Add(stepName string, ctx processor.StepContext) {
 	})
 }
 
-func (s *store) Ordered() []stepResult {
+// totals counts the reported steps by their outcome, allowed failures are counted as warnings.
+type totals struct {
+	passed   int
+	warnings int
+	failed   int
+	skipped  int
+}
+
+func (t totals) String() string {
+	return fmt.Sprintf("%d passed, %d warnings, %d failed, %d skipped", t.passed, t.warnings, t.failed, t.skipped)
+}
+
+// Totals counts the final result of each step, failed attempts of a step which was retried are not counted.
+func (s *store) Totals() totals {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	sort.Slice(s.steps, func(i, j int) bool {
-		var iTags, jTags []string
-		for _, tag := range s.steps[i].result.Tags.Tags() {
-			iTags = append(iTags, fmt.Sprintf("%s:%s", tag.Key, tag.Value))
+	var t totals
+	for _, step := range s.steps {
+		if s.retried(step) {
+			continue
 		}
-		for _, tag := range s.steps[j].result.Tags.Tags() {
-			jTags = append(jTags, fmt.Sprintf("%s:%s", tag.Key, tag.Value))
+
+		switch err := step.result.Error; {
+		case step.result.StartedAt.IsZero():
+		case err == nil:
+			t.passed++
+		case errors.Is(err, processor.ErrAllowFailure):
+			t.warnings++
+		case !processor.AbortOnError(err):
+			t.skipped++
+		default:
+			t.failed++
 		}
+	}
+
+	return t
+}
+
+// retried returns true if a later attempt of the same step was reported.
+func (s *store) retried(step stepResult) bool {
+	attempt, ok := step.result.AttemptOf(step.stepName)
+	if !ok {
+		return false
+	}
+
+	key := tagsKey(step.result)
+	for _, other := range s.steps {
+		if other.stepName != step.stepName || tagsKey(other.result) != key {
+			continue
+		}
+
+		if otherAttempt, ok := other.result.AttemptOf(other.stepName); ok && otherAttempt > attempt {
+			return true
+		}
+	}
+
+	return false
+}
+
+func tagsKey(ctx processor.StepContext) string {
+	var tags []string
+	for _, tag := range ctx.Tags.Tags() {
+		tags = append(tags, fmt.Sprintf("%s:%s", tag.Key, tag.Value))
+	}
 
-		iTagsKey := strings.Join(iTags, "-")
-		jTagsKey := strings.Join(jTags, "-")
+	return strings.Join(tags, "-")
+}
+
+func (s *store) Ordered() []stepResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sort.Slice(s.steps, func(i, j int) bool {
+		iTagsKey := tagsKey(s.steps[i].result)
+		jTagsKey := tagsKey(s.steps[j].result)
 
 		if iTagsKey == jTagsKey {
 			return s.steps[i].result.StartedAt.Before(s.steps[j].result.StartedAt)
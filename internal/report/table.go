@@ -1,6 +1,7 @@
 package report
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -35,6 +36,7 @@ func (r *table) Finalize() error {
 	rows = append(rows, []string{"#", "STEP", "STATUS", "DURATION", "TAGS", "ERROR"})
 	for i, step := range r.store.Ordered() {
 		errMsg, status, duration := r.stringify(step.result)
+		status = step.status(status)
 
 		var tags []string
 		for _, tag := range step.result.Tags.Tags() {
@@ -82,6 +84,8 @@ func (r *table) Finalize() error {
 		fmt.Fprintf(r.w, "%s\n", strings.Join(row, " | "))
 	}
 
+	fmt.Fprintf(r.w, "\n%s\n", r.store.Totals())
+
 	return nil
 }
 
@@ -95,6 +99,12 @@ func (r *table) stringify(step processor.StepContext) (string, string, string) {
 	switch {
 	case step.StartedAt.IsZero():
 		status = tui.StepStatusWaiting
+	case errors.Is(step.Error, processor.ErrAllowFailure):
+		status = tui.StepStatusWarning
+		errMsg = strings.ReplaceAll(step.Error.Error(), "\n", "")
+	case errors.Is(step.Error, processor.ErrTimeout):
+		status = tui.StepStatusTimeout
+		errMsg = strings.ReplaceAll(step.Error.Error(), "\n", "")
 	case step.Error != nil && !processor.AbortOnError(step.Error):
 		status = tui.StepStatusSkipped
 		errMsg = strings.ReplaceAll(step.Error.Error(), "\n", "")
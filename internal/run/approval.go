@@ -0,0 +1,123 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/raffis/rageta/internal/approval"
+	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+type ApprovalType string
+
+var (
+	ApprovalTypeUI      ApprovalType = "ui"
+	ApprovalTypePrompt  ApprovalType = "prompt"
+	ApprovalTypeFile    ApprovalType = "file"
+	ApprovalTypeHTTP    ApprovalType = "http"
+	ApprovalTypeApprove ApprovalType = "approve"
+	ApprovalTypeReject  ApprovalType = "reject"
+)
+
+func (d ApprovalType) String() string {
+	return string(d)
+}
+
+const defaultApprovalHTTPAddr = "127.0.0.1:8585"
+
+type ApprovalOptions struct {
+	Approval string
+}
+
+func (s *ApprovalOptions) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&s.Approval, "approval", "", s.Approval, "How approval steps are decided. One of [ui, prompt, file[=dir], http[=addr], approve, reject]. Defaults to `ui` if the terminal ui is used, `prompt` if stdin is a terminal and `file` otherwise. Approvals without a timeout fail if `file` is not explicitly given. `approve` and `reject` decide all approvals without asking.")
+}
+
+func (s ApprovalOptions) Build() Step {
+	return &Approval{opts: s}
+}
+
+type Approval struct {
+	opts ApprovalOptions
+}
+
+type ApprovalContext struct {
+	Approver processor.Approver
+}
+
+func (s *Approval) Run(rc *RunContext, next Next) error {
+	if rc.DryRun.Plan != nil {
+		rc.Approval.Approver = approval.Always(v1beta1.ApprovalDecisionApprove, "dry-run")
+		return next(rc)
+	}
+
+	approvalType, opts, _ := strings.Cut(s.opts.Approval, "=")
+	elected := approvalType == ""
+	if elected {
+		approvalType = s.electDefault(rc).String()
+	}
+
+	switch approvalType {
+	case ApprovalTypeUI.String():
+		if rc.Output.UI == nil {
+			return fmt.Errorf("approval type %s requires the ui output", approvalType)
+		}
+
+		rc.Approval.Approver = approval.UI(rc.Output.UI)
+	case ApprovalTypePrompt.String():
+		rc.Approval.Approver = approval.Prompt(rc.Output.Stdin, rc.Output.Stderr)
+	case ApprovalTypeFile.String():
+		if opts == "" {
+			opts = filepath.Join(rc.ContextDir.Path, "approvals")
+		}
+
+		rc.Logging.Logger.V(1).Info("approvals are decided by files", "path", opts)
+		rc.Approval.Approver = approval.File(opts)
+
+		// Without a terminal nobody might watch the approval files, only approvals with a timeout are awaited
+		if elected {
+			rc.Approval.Approver = approval.RequireDeadline(rc.Approval.Approver)
+		}
+	case ApprovalTypeHTTP.String():
+		if opts == "" {
+			opts = defaultApprovalHTTPAddr
+		}
+
+		server, err := approval.HTTP(opts)
+		if err != nil {
+			return fmt.Errorf("failed to start approval endpoint: %w", err)
+		}
+
+		defer func() {
+			_ = server.Close()
+		}()
+
+		rc.Logging.Logger.Info("approval endpoint listening", "addr", server.Addr())
+		rc.Approval.Approver = server
+	case ApprovalTypeApprove.String():
+		rc.Approval.Approver = approval.Always(v1beta1.ApprovalDecisionApprove, "")
+	case ApprovalTypeReject.String():
+		rc.Approval.Approver = approval.Always(v1beta1.ApprovalDecisionReject, "")
+	default:
+		return fmt.Errorf("invalid approval type given: %s", s.opts.Approval)
+	}
+
+	return next(rc)
+}
+
+func (s *Approval) electDefault(rc *RunContext) ApprovalType {
+	if rc.Output.UI != nil {
+		return ApprovalTypeUI
+	}
+
+	if f, ok := rc.Output.Stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return ApprovalTypePrompt
+	}
+
+	return ApprovalTypeFile
+}
@@ -0,0 +1,91 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/raffis/rageta/internal/debug"
+	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/internal/styles"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+type BreakpointOptions struct {
+	BreakBefore []string
+	BreakAfter  []string
+}
+
+func (s *BreakpointOptions) BindFlags(flags *pflag.FlagSet) {
+	flags.StringSliceVarP(&s.BreakBefore, "break-before", "", s.BreakBefore, "Pause the pipeline before the given steps are executed. The context can be inspected and inputs and envs changed for the remaining steps.")
+	flags.StringSliceVarP(&s.BreakAfter, "break-after", "", s.BreakAfter, "Pause the pipeline after the given steps were executed. The context can be inspected and inputs and envs changed for the remaining steps.")
+}
+
+func (s BreakpointOptions) Build() Step {
+	return &Breakpoint{opts: s}
+}
+
+type Breakpoint struct {
+	opts BreakpointOptions
+}
+
+type BreakpointContext struct {
+	Breakpointer processor.Breakpointer
+	Before       []string
+	After        []string
+}
+
+func (s *Breakpoint) Run(rc *RunContext, next Next) error {
+	if rc.DryRun.Plan != nil {
+		return next(rc)
+	}
+
+	for _, name := range slices.Concat(s.opts.BreakBefore, s.opts.BreakAfter) {
+		if !slices.ContainsFunc(rc.Provider.Pipeline.Steps, func(step v1beta1.Step) bool {
+			return step.Name == name
+		}) {
+			return fmt.Errorf("unknown step %q given as breakpoint", name)
+		}
+	}
+
+	if f, ok := rc.Output.Stdin.(*os.File); !ok || !term.IsTerminal(int(f.Fd())) {
+		if len(s.opts.BreakBefore) > 0 || len(s.opts.BreakAfter) > 0 {
+			return errors.New("breakpoints require stdin to be a terminal")
+		}
+
+		// Breakpoints of the pipeline spec can't pause a run which is not interactive
+		if steps := specBreakpoints(rc.Provider.Pipeline); len(steps) > 0 {
+			fmt.Fprintf(rc.Output.Stderr, "%s\n", styles.Highlight.Render(fmt.Sprintf("Breakpoints of steps %s are ignored as stdin is not a terminal", strings.Join(steps, ", "))))
+		}
+
+		return next(rc)
+	}
+
+	var terminal debug.Terminal
+	if rc.Output.UI != nil {
+		terminal = rc.Output.UI
+	}
+
+	// Secrets in the printed context are masked
+	rc.Breakpoint.Breakpointer = debug.Breakpoint(terminal, rc.Output.Stdin, rc.Secrets.Store.Writer(rc.Output.Stdout))
+	rc.Breakpoint.Before = s.opts.BreakBefore
+	rc.Breakpoint.After = s.opts.BreakAfter
+
+	return next(rc)
+}
+
+// specBreakpoints returns the steps with a breakpoint set in the pipeline spec.
+func specBreakpoints(spec v1beta1.Pipeline) []string {
+	var steps []string
+	for _, step := range spec.Steps {
+		if step.Breakpoint {
+			steps = append(steps, step.Name)
+		}
+	}
+
+	return steps
+}
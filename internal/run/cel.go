@@ -5,7 +5,10 @@ import (
 	"reflect"
 
 	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
 	"github.com/google/cel-go/ext"
+	"github.com/raffis/rageta/internal/changes"
 	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
 )
 
@@ -24,6 +27,22 @@ type CELContext struct {
 }
 
 func (s *CEL) Run(rc *RunContext, next Next) error {
+	celEnv, err := NewCELEnv(rc.Changes.Detector)
+	if err != nil {
+		return err
+	}
+
+	rc.CEL.Env = celEnv
+	return next(rc)
+}
+
+// NewCELEnv creates the environment all pipeline expressions are evaluated in.
+// The function changed(pattern) reports changes using the detector, without a detector every pattern is changed.
+func NewCELEnv(detector changes.Detector) (*cel.Env, error) {
+	if detector == nil {
+		detector = changes.Always()
+	}
+
 	celEnv, err := cel.NewEnv(
 		ext.Strings(),
 		ext.Math(),
@@ -36,13 +55,26 @@ func (s *CEL) Run(rc *RunContext, next Next) error {
 			reflect.TypeOf(&v1beta1.ParamValue{}),
 			reflect.TypeOf(&v1beta1.Output{}),
 			reflect.TypeOf(&v1beta1.ContainerStatus{}),
+			reflect.TypeOf(&v1beta1.Loop{}),
+			reflect.TypeOf(&v1beta1.Attempt{}),
 		),
 		cel.Variable("context", cel.ObjectType("v1beta1.Context")),
+		cel.Function("changed",
+			cel.Overload("changed_string", []*cel.Type{cel.StringType}, cel.BoolType,
+				cel.UnaryBinding(func(pattern ref.Val) ref.Val {
+					changed, err := detector.Changed(string(pattern.(types.String)))
+					if err != nil {
+						return types.WrapErr(err)
+					}
+
+					return types.Bool(changed)
+				}),
+			),
+		),
 	)
 	if err != nil {
-		return fmt.Errorf("setup cel env failed: %w", err)
+		return nil, fmt.Errorf("setup cel env failed: %w", err)
 	}
 
-	rc.CEL.Env = celEnv
-	return next(rc)
+	return celEnv, nil
 }
@@ -0,0 +1,103 @@
+package run
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/raffis/rageta/internal/changes"
+	"github.com/spf13/pflag"
+)
+
+type ChangesType string
+
+var (
+	ChangesTypeGit    ChangesType = "git"
+	ChangesTypeHashes ChangesType = "hashes"
+	ChangesTypeNone   ChangesType = "none"
+)
+
+func (d ChangesType) String() string {
+	return string(d)
+}
+
+type ChangesOptions struct {
+	Changes  string
+	CacheDir string
+}
+
+func (s *ChangesOptions) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&s.Changes, "changes", "", s.Changes, "How changed files are detected for `changed` conditions and step sources. One of [git[=base-ref], hashes, none]. Defaults to `none` which treats all files as changed and executes every step. `git` compares against the merge base with the default branch while `hashes` compares against the file hashes recorded by the last successful run.")
+}
+
+func (s ChangesOptions) Build() Step {
+	return &Changes{opts: s}
+}
+
+type Changes struct {
+	opts ChangesOptions
+}
+
+type ChangesContext struct {
+	Detector changes.Detector
+}
+
+func (s *Changes) Run(rc *RunContext, next Next) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	// Steps are only skipped if change detection was explicitly enabled
+	changesType, base, _ := strings.Cut(s.opts.Changes, "=")
+	if changesType == "" {
+		changesType = ChangesTypeNone.String()
+	}
+
+	switch changesType {
+	case ChangesTypeGit.String():
+		rc.Changes.Detector = changes.Git(dir, base)
+	case ChangesTypeNone.String():
+		rc.Changes.Detector = changes.Always()
+	case ChangesTypeHashes.String():
+		stateFile, err := s.stateFile(dir, rc.Provider.Ref)
+		if err != nil {
+			return err
+		}
+
+		detector := changes.Hashes(dir, stateFile)
+		rc.Changes.Detector = detector
+
+		if err := next(rc); err != nil || rc.DryRun.Plan != nil {
+			return err
+		}
+
+		if err := detector.Save(); err != nil {
+			rc.Logging.Logger.Error(err, "failed to record source hashes")
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("invalid changes type given: %s", s.opts.Changes)
+	}
+
+	return next(rc)
+}
+
+// stateFile returns the file the source hashes of the pipeline are recorded to for the working directory.
+func (s *Changes) stateFile(dir, ref string) (string, error) {
+	cacheDir := s.opts.CacheDir
+	if cacheDir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to lookup cache dir: %w", err)
+		}
+
+		cacheDir = filepath.Join(userCacheDir, "rageta")
+	}
+
+	key := sha256.Sum256([]byte(dir + "\x00" + ref))
+	return filepath.Join(cacheDir, "changes", fmt.Sprintf("%x.json", key)), nil
+}
@@ -0,0 +1,52 @@
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangesDefaultExecutesSources(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644))
+	t.Chdir(dir)
+
+	spec := &v1beta1.Step{
+		Name: "build",
+		StepOptions: v1beta1.StepOptions{
+			Sources: []v1beta1.Source{{Match: "*.go"}},
+		},
+	}
+
+	// Neither the first run nor a later run without any changes skip the step
+	cacheDir := t.TempDir()
+	for range 2 {
+		executed := false
+		step := ChangesOptions{CacheDir: cacheDir}.Build()
+		err := step.Run(NewContext(), func(rc *RunContext) error {
+			next := func(ctx processor.StepContext) (processor.StepContext, error) {
+				executed = true
+				return ctx, nil
+			}
+
+			if sources := processor.WithSources(rc.Changes.Detector)(spec); sources != nil {
+				var err error
+				next, err = sources.Bootstrap(nil, next)
+				if err != nil {
+					return err
+				}
+			}
+
+			_, err := next(processor.NewContext())
+			return err
+		})
+
+		require.NoError(t, err)
+		assert.True(t, executed)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/raffis/rageta/internal/dockersetup"
+	"github.com/raffis/rageta/internal/dryrun"
 	"github.com/raffis/rageta/internal/kubesetup"
 	cruntime "github.com/raffis/rageta/internal/runtime"
 	"github.com/spf13/pflag"
@@ -88,6 +89,11 @@ type ContainerRuntimeContext struct {
 }
 
 func (s *ContainerRuntime) Run(rc *RunContext, next Next) error {
+	if rc.DryRun.Plan != nil {
+		rc.ContainerRuntime.Driver = dryrun.NewDriver()
+		return next(rc)
+	}
+
 	driver, err := s.createContainerRuntime(rc.Context, rc.Logging.Logger)
 	if err != nil {
 		return err
@@ -27,6 +27,14 @@ type RunContext struct {
 	Pipeline         PipelineContext
 	Template         TemplateContext
 	Execution        ExecutionContext
+	StepContext      StepContextContext
+	DryRun           DryRunContext
+	Approval         ApprovalContext
+	Changes          ChangesContext
+	Locks            LocksContext
+	Watch            WatchContext
+	DebugOnFailure   DebugOnFailureContext
+	Breakpoint       BreakpointContext
 }
 
 func NewContext() *RunContext {
@@ -0,0 +1,55 @@
+package run
+
+import (
+	"errors"
+	"os"
+
+	"github.com/raffis/rageta/internal/debug"
+	"github.com/raffis/rageta/internal/processor"
+	cruntime "github.com/raffis/rageta/internal/runtime"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+type DebugOnFailureOptions struct {
+	DebugOnFailure bool
+}
+
+func (s *DebugOnFailureOptions) BindFlags(flags *pflag.FlagSet) {
+	flags.BoolVarP(&s.DebugOnFailure, "debug-on-failure", "", s.DebugOnFailure, "Start an interactive shell in a copy of the container of a failed step. Once the shell exits the step can be retried, skipped or the pipeline is aborted.")
+}
+
+func (s DebugOnFailureOptions) Build() Step {
+	return &DebugOnFailure{opts: s}
+}
+
+type DebugOnFailure struct {
+	opts DebugOnFailureOptions
+}
+
+type DebugOnFailureContext struct {
+	Debugger processor.Debugger
+}
+
+func (s *DebugOnFailure) Run(rc *RunContext, next Next) error {
+	if !s.opts.DebugOnFailure || rc.DryRun.Plan != nil {
+		return next(rc)
+	}
+
+	driver, ok := rc.ContainerRuntime.Driver.(cruntime.Debugger)
+	if !ok {
+		return errors.New("--debug-on-failure is not supported by the container runtime")
+	}
+
+	if f, ok := rc.Output.Stdin.(*os.File); !ok || !term.IsTerminal(int(f.Fd())) {
+		return errors.New("--debug-on-failure requires stdin to be a terminal")
+	}
+
+	var terminal debug.Terminal
+	if rc.Output.UI != nil {
+		terminal = rc.Output.UI
+	}
+
+	rc.DebugOnFailure.Debugger = debug.Shell(driver, terminal, rc.Output.Stdin, rc.Output.Stdout)
+	return next(rc)
+}
@@ -0,0 +1,72 @@
+package run
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/raffis/rageta/internal/dryrun"
+	"github.com/spf13/pflag"
+)
+
+type dryRunFormat string
+
+var (
+	dryRunFormatText dryRunFormat = "text"
+	dryRunFormatJSON dryRunFormat = "json"
+)
+
+func (d dryRunFormat) String() string {
+	return string(d)
+}
+
+type DryRunOptions struct {
+	Format string
+}
+
+func (s *DryRunOptions) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&s.Format, "dry-run", "", s.Format, "Do not start any containers but print the execution plan instead. One of [text, json].")
+	flags.Lookup("dry-run").NoOptDefVal = dryRunFormatText.String()
+}
+
+func (s DryRunOptions) Enabled() bool {
+	return s.Format != ""
+}
+
+func (s DryRunOptions) Build() Step {
+	return &DryRun{opts: s}
+}
+
+type DryRun struct {
+	opts DryRunOptions
+}
+
+type DryRunContext struct {
+	Plan *dryrun.Plan
+}
+
+func (s *DryRun) Run(rc *RunContext, next Next) error {
+	if !s.opts.Enabled() {
+		return next(rc)
+	}
+
+	if s.opts.Format != dryRunFormatText.String() && s.opts.Format != dryRunFormatJSON.String() {
+		return fmt.Errorf("invalid dry-run format given: %s", s.opts.Format)
+	}
+
+	rc.DryRun.Plan = dryrun.NewPlan()
+	err := next(rc)
+
+	// The plan is printed even if the pipeline failed as it shows the steps executed so far.
+	// Commands and args are recorded after substitution, secrets in the plan are masked.
+	w := rc.Secrets.Store.Writer(rc.Output.Stdout)
+
+	var writeErr error
+	switch s.opts.Format {
+	case dryRunFormatJSON.String():
+		writeErr = rc.DryRun.Plan.WriteJSON(w)
+	default:
+		writeErr = rc.DryRun.Plan.WriteText(w)
+	}
+
+	return errors.Join(err, writeErr)
+}
@@ -0,0 +1,39 @@
+package run
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/raffis/rageta/internal/dryrun"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunMasksSecrets(t *testing.T) {
+	for _, format := range []dryRunFormat{dryRunFormatText, dryRunFormatJSON} {
+		t.Run(format.String(), func(t *testing.T) {
+			out := &bytes.Buffer{}
+			rc := NewContext()
+			rc.Output.Stdout = out
+			rc.Secrets.Store.AddSecrets([]byte("s3cr3t"))
+
+			step := DryRunOptions{Format: format.String()}.Build()
+			err := step.Run(rc, func(rc *RunContext) error {
+				planned := &dryrun.Step{Name: "deploy"}
+				planned.AddContainer(dryrun.Container{
+					Image:   "alpine",
+					Command: []string{"deploy"},
+					Args:    []string{"--token", "s3cr3t"},
+				})
+
+				rc.DryRun.Plan.Add(planned)
+				return nil
+			})
+
+			require.NoError(t, err)
+			assert.Contains(t, out.String(), "deploy")
+			assert.Contains(t, out.String(), "***")
+			assert.NotContains(t, out.String(), "s3cr3t")
+		})
+	}
+}
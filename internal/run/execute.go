@@ -3,9 +3,13 @@ package run
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/raffis/rageta/internal/pipeline"
 	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
 	"github.com/sethvargo/go-retry"
 	"github.com/spf13/pflag"
 )
@@ -13,11 +17,15 @@ import (
 type ExecuteOptions struct {
 	MaxRetries uint64
 	Entrypoint string
+	Only       []string
+	OnlyTags   []string
 }
 
 func (s *ExecuteOptions) BindFlags(flags *pflag.FlagSet) {
 	flags.Uint64VarP(&s.MaxRetries, "retry", "", s.MaxRetries, "Retry pipeline if a failure occurred.")
 	flags.StringVarP(&s.Entrypoint, "entrypoint", "t", s.Entrypoint, "Entrypoint for the given pipeline. The pipelines default is used otherwise.")
+	flags.StringSliceVarP(&s.Only, "only", "", s.Only, "Only execute the given steps and the steps they depend on. Dependencies which are done within the recovered context are not executed again.")
+	flags.StringSliceVarP(&s.OnlyTags, "only-tags", "", s.OnlyTags, "Only execute steps matching any of the tags and the steps they depend on. Format is `key=value` or `key`.")
 }
 
 func (s ExecuteOptions) Build() Step {
@@ -47,7 +55,16 @@ func (e *pipelineExecutionError) Unwrap() error {
 func (s *Execute) Run(rc *RunContext, next Next) error {
 	rc.Execution.StepContext.Context = rc.Context
 
-	pipelineCmd, err := rc.Pipeline.Builder.Build(rc.Provider.Pipeline, s.opts.Entrypoint, rc.Inputs.Args, rc.Execution.StepContext)
+	spec := rc.Provider.Pipeline
+	if len(s.opts.Only) > 0 || len(s.opts.OnlyTags) > 0 {
+		var err error
+		spec, err = s.selectSteps(rc)
+		if err != nil {
+			return err
+		}
+	}
+
+	pipelineCmd, err := rc.Pipeline.Builder.Build(spec, s.opts.Entrypoint, rc.Inputs.Args, rc.Execution.StepContext, processor.RecoverSteps(rc.Execution.StepContext))
 	if err != nil {
 		return err
 	}
@@ -60,6 +77,30 @@ func (s *Execute) Run(rc *RunContext, next Next) error {
 	return next(rc)
 }
 
+// selectSteps reduces the pipeline to the selected steps and their dependencies.
+func (s *Execute) selectSteps(rc *RunContext) (v1beta1.Pipeline, error) {
+	targets := slices.Clone(s.opts.Only)
+	if len(s.opts.OnlyTags) > 0 {
+		tagged := pipeline.StepsByTags(rc.Provider.Pipeline, s.opts.OnlyTags)
+		if len(tagged) == 0 {
+			return rc.Provider.Pipeline, fmt.Errorf("no steps match the tags %s", strings.Join(s.opts.OnlyTags, ","))
+		}
+
+		targets = append(targets, tagged...)
+	}
+
+	spec, err := pipeline.Select(rc.Provider.Pipeline, s.opts.Entrypoint, targets, func(name string) bool {
+		result, ok := rc.Execution.StepContext.Steps[name]
+		return ok && result.Error == nil && !result.EndedAt.IsZero()
+	})
+
+	if err != nil {
+		return spec, fmt.Errorf("failed to select steps: %w", err)
+	}
+
+	return spec, nil
+}
+
 func (s *Execute) retryRun(rc *RunContext, pipelineCmd processor.Executable) error {
 	var inner retry.Backoff = retry.BackoffFunc(func() (time.Duration, bool) { return 0, true })
 	if s.opts.MaxRetries > 0 {
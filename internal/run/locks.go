@@ -0,0 +1,50 @@
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/raffis/rageta/internal/locks"
+	"github.com/raffis/rageta/internal/output"
+	"github.com/raffis/rageta/internal/processor"
+)
+
+const lockPollInterval = 250 * time.Millisecond
+
+type LocksOptions struct {
+	Dir string
+}
+
+func (s LocksOptions) Build() Step {
+	return &Locks{opts: s}
+}
+
+type Locks struct {
+	opts LocksOptions
+}
+
+type LocksContext struct {
+	Locker locks.Locker
+	Notify processor.BlockedNotifier
+}
+
+func (s *Locks) Run(rc *RunContext, next Next) error {
+	// Nothing is executed in a dry run, there is no need to wait for locks held by others
+	if rc.DryRun.Plan != nil {
+		return next(rc)
+	}
+
+	dir := s.opts.Dir
+	if dir == "" {
+		dir = filepath.Join(os.TempDir(), "rageta-locks")
+	}
+
+	rc.Locks.Locker = locks.File(dir, lockPollInterval)
+
+	if rc.Output.UI != nil {
+		rc.Locks.Notify = output.UIBlocked(rc.Output.UI)
+	}
+
+	return next(rc)
+}
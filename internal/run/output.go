@@ -1,6 +1,7 @@
 package run
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -74,6 +75,8 @@ type OutputContext struct {
 	Expand        bool
 	InternalSteps bool
 	Type          string
+	UI            *tea.Program
+	Stdin         io.Reader
 	Stdout        io.Writer
 	Stderr        io.Writer
 }
@@ -95,15 +98,19 @@ func (s *Output) Run(rc *RunContext, next Next) error {
 	rc.Output.Expand = s.opts.Expand
 	rc.Output.InternalSteps = s.opts.InternalSteps
 	rc.Output.Type = s.opts.Output
+	rc.Output.UI = s.tuiApp
 
 	err = next(rc)
 	if s.tuiApp == nil {
 		return err
 	}
 
-	if err != nil {
+	switch {
+	case errors.Is(err, processor.ErrTimeout):
+		s.tuiApp.Send(tui.PipelineDoneMsg{Status: tui.StepStatusTimeout, Error: err})
+	case err != nil:
 		s.tuiApp.Send(tui.PipelineDoneMsg{Status: tui.StepStatusFailed, Error: err})
-	} else {
+	default:
 		s.tuiApp.Send(tui.PipelineDoneMsg{Status: tui.StepStatusDone, Error: nil})
 	}
 
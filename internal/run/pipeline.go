@@ -53,10 +53,17 @@ func (s *Pipeline) stepPipeline(rc *RunContext, pipeline *processor.PipelineBuil
 	}
 
 	return func(spec v1beta1.Step) []processor.Bootstraper {
+		teardown := rc.Teardown.Teardown
+		if rc.Watch.Teardown != nil {
+			teardown = rc.Watch.Teardown(spec.Name)
+		}
+
 		processors := processor.Builder(&spec,
 			processor.WithRecover(),
+			processor.WithBreakpoint(rc.Breakpoint.Breakpointer, rc.Breakpoint.Before, rc.Breakpoint.After),
+			processor.WithRetry(rc.CEL.Env),
 			processor.WithReport(rc.Report.Factory),
-			processor.WithRetry(),
+			processor.WithCheckpoint(rc.StepContext.Checkpoint),
 			processor.WithResult(),
 			processor.WithTmpDir(),
 			processor.WithInputVars(rc.CEL.Env),
@@ -71,19 +78,26 @@ func (s *Pipeline) stepPipeline(rc *RunContext, pipeline *processor.PipelineBuil
 			processor.WithLogger(rc.Logging.Logger, rc.Logging.Builder, rc.Logging.Detached),
 			processor.WithOtelMetrics(rc.Otel.Meter),
 			processor.WithSkipBlacklist(s.opts.SkipSteps),
-			processor.WithGarbageCollector(!rc.Teardown.Enabled, rc.ContainerRuntime.Driver, rc.Teardown.Teardown),
-			processor.WithAllowFailure(),
+			processor.WithGarbageCollector(!rc.Teardown.Enabled, rc.ContainerRuntime.Driver, teardown),
+			processor.WithAllowFailure(rc.CEL.Env),
 			processor.WithTimeout(),
-			processor.WithSkipDone(s.opts.SkipDone),
-			processor.WithIf(rc.CEL.Env),
+			processor.WithSkipDone(s.opts.SkipDone || rc.StepContext.Resume),
+			processor.WithDryRun(rc.DryRun.Plan),
+			processor.WithSwitchSkip(),
+			processor.WithIf(rc.CEL.Env, rc.Changes.Detector),
+			processor.WithSources(rc.Changes.Detector),
 			processor.WithTemplate(rc.Template.Container),
 			processor.WithNeeds(),
+			processor.WithLock(rc.Locks.Locker, rc.Locks.Notify),
 			processor.WithStdioRedirect(false),
 			processor.WithMaxConcurrent(pool),
 			processor.WithContainerLogs(!s.opts.SkipContainerLogs, rc.Secrets.Store),
-			processor.WithRun(rc.ImagePolicy.PullPolicy, rc.ContainerRuntime.Driver, rc.Output.Factory, rc.Teardown.Teardown),
+			processor.WithRun(rc.ImagePolicy.PullPolicy, rc.ContainerRuntime.Driver, rc.Output.Factory, teardown, rc.DebugOnFailure.Debugger),
 			processor.WithInherit(*pipeline, rc.Provider.Provider),
+			processor.WithApproval(rc.Approval.Approver),
 			processor.WithAnd(),
+			processor.WithSwitch(rc.CEL.Env),
+			processor.WithForEach(rc.CEL.Env),
 			processor.WithConcurrent(),
 			processor.WithPipe(false),
 		)
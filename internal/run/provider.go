@@ -1,19 +1,25 @@
 package run
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 
+	"github.com/go-logr/logr"
 	"github.com/gofrs/flock"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/raffis/rageta/internal/ocisetup"
 	"github.com/raffis/rageta/internal/provider"
 	cruntime "github.com/raffis/rageta/internal/runtime"
 	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	pkgv1beta1 "github.com/raffis/rageta/pkg/apis/package/v1beta1"
+	"github.com/raffis/rageta/pkg/http/middleware"
 	"github.com/spf13/pflag"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
@@ -21,14 +27,21 @@ import (
 )
 
 type ProviderOptions struct {
-	OCI    *ocisetup.Options
-	DBPath string
+	OCI             *ocisetup.Options
+	DBPath          string
+	CacheDir        string
+	Verify          bool
+	TrustPolicyPath string
+	// Inputs are the key=value pairs given by --input, they are passed to starlark pipeline definitions.
+	Inputs []string
 }
 
 func (s *ProviderOptions) BindFlags(flags *pflag.FlagSet) {
 	ociFlags := pflag.NewFlagSet("oci", pflag.ExitOnError)
 	s.OCI.BindFlags(ociFlags)
 	flags.AddFlagSet(ociFlags)
+	flags.BoolVar(&s.Verify, "verify", s.Verify, "Verify signatures of OCI pipelines (including inherited ones) against the trust policy.")
+	flags.StringVar(&s.TrustPolicyPath, "trust-policy", s.TrustPolicyPath, "Path to the trust policy listing the public keys per repository glob. Required with --verify.")
 }
 
 func (s ProviderOptions) Build() Step {
@@ -54,9 +67,9 @@ type ProviderContext struct {
 
 func (s *Provider) Run(rc *RunContext, next Next) error {
 	store, persistDB := CreateProvider(
+		rc.Logging.Logger,
 		rc.ImagePolicy.PullPolicy,
-		s.opts.DBPath,
-		s.opts.OCI,
+		s.opts,
 	)
 	rc.Provider.Provider = store
 	defer func() {
@@ -71,8 +84,16 @@ func (s *Provider) Run(rc *RunContext, next Next) error {
 		rc.Provider.Ref = ref
 	}
 
+	// Inputs are validated against the pipeline inputs once the pipeline is resolved
+	inputs := make(map[string]string, len(s.opts.Inputs))
+	for _, input := range s.opts.Inputs {
+		if name, value, ok := strings.Cut(input, "="); ok {
+			inputs[name] = value
+		}
+	}
+
 	rc.Logging.Logger.V(3).Info("resolve pipeline reference", "source", ref)
-	pipeline, err := store.Resolve(rc.Context, ref)
+	pipeline, err := store.Resolve(provider.WithStarlarkInputs(rc.Context, inputs), ref)
 	if err != nil {
 		return err
 	}
@@ -82,12 +103,16 @@ func (s *Provider) Run(rc *RunContext, next Next) error {
 }
 
 func CreateProvider(
+	logger logr.Logger,
 	imagePullPolicy cruntime.PullImagePolicy,
-	dbPath string,
-	ociOptions *ocisetup.Options,
+	opts ProviderOptions,
 ) (provider.Interface, func() error) {
+	dbPath := opts.DBPath
+	ociOptions := opts.OCI
+
 	scheme := kruntime.NewScheme()
 	_ = v1beta1.AddToScheme(scheme)
+	_ = pkgv1beta1.AddToScheme(scheme)
 
 	factory := serializer.NewCodecFactory(scheme)
 	decoder := factory.UniversalDeserializer()
@@ -116,34 +141,80 @@ func CreateProvider(
 		if err != nil {
 			return nil, fmt.Errorf("failed to open local database: %w", err)
 		}
+
+		// Only oci pipelines are signed, git and http pipelines can not be verified
+		if opts.Verify && !provider.IsGitRef(ref) && !provider.IsHTTPRef(ref) {
+			if app, err := localDB.Get(ref); err == nil && !app.Verified {
+				return nil, fmt.Errorf("db: pipeline %q has not been verified", ref)
+			}
+		}
+
 		return provider.WithLocalDB(localDB)(ctx, ref)
 	}
 
 	ociProviderWrapper := func(ctx context.Context, ref string) (io.Reader, error) {
 		ociOptions.URL = ref
+		if versionRef, ok := provider.ParseVersionRef(ref); ok {
+			ociOptions.URL = versionRef.Repository
+		}
+
 		ociClient, err := ociOptions.Build(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to build oci client: %w", err)
 		}
 
-		r, err := provider.WithOCI(ociClient)(ctx, ref)
+		remoteOpts, err := ociOptions.RemoteOptions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build registry options: %w", err)
+		}
+
+		localDB, err := openDB()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open local database: %w", err)
+		}
+
+		ociOpts := []provider.OCIOption{
+			provider.WithOCIDatabase(localDB),
+			provider.WithOCITagLister(provider.NewRegistryTagLister(remoteOpts...)),
+		}
+
+		if opts.Verify {
+			verifier, err := createVerifier(opts.TrustPolicyPath, decoder, remoteOpts)
+			if err != nil {
+				return nil, err
+			}
+
+			ociOpts = append(ociOpts, provider.WithOCIVerifier(verifier))
+		}
+
+		r, err := provider.WithOCI(ociClient, ociOpts...)(ctx, ref)
 		if err != nil {
 			return nil, fmt.Errorf("failed to pull image from oci registry: %w", err)
 		}
 
+		return r, nil
+	}
+
+	gitProviderWrapper := func(ctx context.Context, ref string) (io.Reader, error) {
 		localDB, err := openDB()
 		if err != nil {
-			return r, fmt.Errorf("failed to open local database: %w", err)
+			return nil, fmt.Errorf("failed to open local database: %w", err)
 		}
 
-		manifest, err := io.ReadAll(r)
+		return provider.WithGit(filepath.Join(opts.CacheDir, "git"), localDB)(ctx, ref)
+	}
+
+	httpClient := &http.Client{
+		Transport: middleware.NewLogger(logger, http.DefaultTransport),
+	}
+
+	httpProviderWrapper := func(ctx context.Context, ref string) (io.Reader, error) {
+		localDB, err := openDB()
 		if err != nil {
-			return r, fmt.Errorf("failed to read manifest: %w", err)
+			return nil, fmt.Errorf("failed to open local database: %w", err)
 		}
 
-		r = bytes.NewReader(manifest)
-		err = localDB.Add(ref, manifest)
-		return r, err
+		return provider.WithHTTP(httpClient, localDB)(ctx, ref)
 	}
 
 	providers := []provider.Resolver{
@@ -151,12 +222,22 @@ func CreateProvider(
 		provider.WithRagetafile(),
 	}
 	if imagePullPolicy == cruntime.PullImagePolicyAlways {
-		providers = append(providers, ociProviderWrapper, localDBProviderWrapper)
+		providers = append(providers, gitProviderWrapper, httpProviderWrapper, ociProviderWrapper, localDBProviderWrapper)
 	} else {
-		providers = append(providers, localDBProviderWrapper, ociProviderWrapper)
+		providers = append(providers, localDBProviderWrapper, gitProviderWrapper, httpProviderWrapper, ociProviderWrapper)
 	}
 
-	return provider.New(decoder, providers...), func() error {
+	lookupAlias := func(alias string) (string, bool) {
+		localDB, err := openDB()
+		if err != nil {
+			return "", false
+		}
+
+		return localDB.Lookup(alias)
+	}
+
+	store := provider.WithAliases(provider.New(decoder, providers...), lookupAlias)
+	return provider.WithStepPackages(provider.WithExtends(store), decoder), func() error {
 		if localDB == nil {
 			return nil
 		}
@@ -164,6 +245,48 @@ func CreateProvider(
 	}
 }
 
+func createVerifier(trustPolicyPath string, decoder kruntime.Decoder, remoteOpts []remote.Option) (*provider.TrustPolicyVerifier, error) {
+	if trustPolicyPath == "" {
+		return nil, errors.New("a trust policy is required to verify pipelines")
+	}
+
+	f, err := os.Open(trustPolicyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trust policy: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	policy, err := provider.LoadTrustPolicy(f, decoder)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.NewTrustPolicyVerifier(policy, provider.NewRegistrySignatureFetcher(remoteOpts...)), nil
+}
+
+// OpenDatabase reads the database from the given path.
+func OpenDatabase(dbPath string) (*provider.Database, error) {
+	scheme := kruntime.NewScheme()
+	_ = v1beta1.AddToScheme(scheme)
+	factory := serializer.NewCodecFactory(scheme)
+	decoder := factory.UniversalDeserializer()
+	encoder := json.NewYAMLSerializer(json.DefaultMetaFactory, scheme, scheme)
+
+	dbFile, err := os.OpenFile(dbPath, os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	defer func() {
+		_ = dbFile.Close()
+	}()
+
+	return provider.OpenDatabase(dbFile, decoder, encoder)
+}
+
 // PersistDatabase writes the in-memory database to the given path.
 func PersistDatabase(dbPath string, db *provider.Database) error {
 	scheme := kruntime.NewScheme()
@@ -23,9 +23,10 @@ func Builder(steps ...Step) *Runner {
 	return result
 }
 
-func (r *Runner) Run(ctx context.Context, args []string, _ io.Reader, stdout, stderr io.Writer) (rc *RunContext, err error) {
+func (r *Runner) Run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) (rc *RunContext, err error) {
 	rc = NewContext()
 	rc.Context = ctx
+	rc.Output.Stdin = stdin
 	rc.Output.Stdout = stdout
 	rc.Output.Stderr = stderr
 	rc.Provider.Args = args
@@ -70,6 +71,13 @@ type Options struct {
 	TagsOptions             TagsOptions
 	SummaryOptions          SummaryOptions
 	StepContextOptions      StepContextOptions
+	DryRunOptions           DryRunOptions
+	ApprovalOptions         ApprovalOptions
+	ChangesOptions          ChangesOptions
+	LocksOptions            LocksOptions
+	WatchOptions            WatchOptions
+	DebugOnFailureOptions   DebugOnFailureOptions
+	BreakpointOptions       BreakpointOptions
 }
 
 func (s *Options) BindFlags(flags *pflag.FlagSet) {
@@ -83,6 +91,11 @@ func (s *Options) BindFlags(flags *pflag.FlagSet) {
 	s.EventsOptions.BindFlags(flags)
 	s.ForkOptions.BindFlags(flags)
 	s.ContainerRuntimeOptions.BindFlags(flags)
+	s.DryRunOptions.BindFlags(flags)
+	s.ApprovalOptions.BindFlags(flags)
+	s.DebugOnFailureOptions.BindFlags(flags)
+	s.BreakpointOptions.BindFlags(flags)
+	s.ChangesOptions.BindFlags(flags)
 	s.OtelOptions.BindFlags(flags)
 	s.LoggingOptions.BindFlags(flags)
 	s.TagsOptions.BindFlags(flags)
@@ -90,6 +103,7 @@ func (s *Options) BindFlags(flags *pflag.FlagSet) {
 	s.SecretOptions.BindFlags(flags)
 	s.ProviderOptions.BindFlags(flags)
 	s.ExecuteOptions.BindFlags(flags)
+	s.WatchOptions.BindFlags(flags)
 	s.InputsOptions.BindFlags(flags)
 	s.PipelineOptions.BindFlags(flags)
 }
@@ -103,6 +117,7 @@ func DefaultOptions() Options {
 		ProviderOptions:         NewProviderOptions(),
 		EventsOptions:           NewEventsOptions(),
 		ReportOptions:           NewReportOptions(),
+		WatchOptions:            NewWatchOptions(),
 	}
 }
 
@@ -112,6 +127,7 @@ func (o Options) Build() *Runner {
 		o.ContextDirOptions.Build(),
 		o.StepContextOptions.Build(),
 		o.SecretOptions.Build(),
+		o.DryRunOptions.Build(),
 		o.ReportOptions.Build(),
 		o.OtelOptions.Build(),
 		o.LoggingOptions.Build(),
@@ -120,15 +136,21 @@ func (o Options) Build() *Runner {
 		o.TemplateOptions.Build(),
 		o.TeardownOptions.Build(),
 		o.EventsOptions.Build(),
-		o.CELOptions.Build(),
 		o.TagsOptions.Build(),
 		o.ContainerRuntimeOptions.Build(),
 		o.ForkOptions.Build(),
 		o.LifecycleOptions.Build(),
 		o.ProviderOptions.Build(),
+		o.ChangesOptions.Build(),
+		o.CELOptions.Build(),
 		o.PipelineOptions.Build(),
 		o.InputsOptions.Build(),
 		o.OutputOptions.Build(),
+		o.ApprovalOptions.Build(),
+		o.DebugOnFailureOptions.Build(),
+		o.BreakpointOptions.Build(),
+		o.LocksOptions.Build(),
+		o.WatchOptions.Build(),
 		o.ExecuteOptions.Build(),
 	)
 }
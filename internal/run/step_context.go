@@ -3,9 +3,12 @@ package run
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/raffis/rageta/internal/processor"
 	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
@@ -14,6 +17,8 @@ import (
 
 type StepContextOptions struct {
 	RecoverFrom string
+	Resume      string
+	From        string
 }
 
 func (s StepContextOptions) Build() Step {
@@ -24,6 +29,8 @@ func (s StepContextOptions) Build() Step {
 
 func (s *StepContextOptions) BindFlags(flags *pflag.FlagSet) {
 	flags.StringVarP(&s.RecoverFrom, "recover", "", s.RecoverFrom, "Recover from previous execution. Path to context directory.")
+	flags.StringVarP(&s.Resume, "resume", "", s.Resume, "Resume a previous execution. Path to context directory. Steps which completed successfully are not executed again.")
+	flags.StringVarP(&s.From, "from", "", s.From, "Resume from the given step. It is executed again as well as all steps which did not complete before it was started.")
 }
 
 type StepContext struct {
@@ -31,47 +38,101 @@ type StepContext struct {
 }
 
 type StepContextContext struct {
+	// Resume is set if steps recorded as done are skipped
+	Resume     bool
+	Checkpoint processor.Checkpointer
 }
 
 func (s *StepContext) Run(rc *RunContext, next Next) error {
 	stepCtx := processor.NewContext()
 	rc.Execution.StepContext = stepCtx
 
-	if err := s.recoverContext(&stepCtx, s.opts.RecoverFrom); err != nil {
+	if s.opts.From != "" && s.opts.Resume == "" {
+		return errors.New("--from requires --resume")
+	}
+
+	recoverFrom := s.opts.RecoverFrom
+	if s.opts.Resume != "" {
+		recoverFrom = s.opts.Resume
+		rc.StepContext.Resume = true
+	}
+
+	if err := s.recoverContext(&stepCtx, recoverFrom); err != nil {
 		return err
 	}
 
+	checkpoint := &checkpoint{
+		path:  filepath.Join(rc.ContextDir.Path, "context.json"),
+		steps: make(map[string]*processor.StepContext),
+	}
+
+	rc.StepContext.Checkpoint = checkpoint.store
 	rc.Execution.StepContext = stepCtx
 	err := next(rc)
 
-	if storeErr := s.storeContext(rc.Execution.StepContext, rc.ContextDir.Path); storeErr != nil {
+	if storeErr := storeContext(rc.Execution.StepContext, checkpoint.path); storeErr != nil {
 		return errors.Join(err, storeErr)
 	}
 
 	return err
 }
 
-func (s *StepContext) storeContext(stepCtx processor.StepContext, contextDir string) error {
-	contextPath := filepath.Join(contextDir, "context.json")
-	f, err := os.OpenFile(contextPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0700)
+// checkpoint persists the results of all completed steps.
+type checkpoint struct {
+	mu    sync.Mutex
+	path  string
+	steps map[string]*processor.StepContext
+}
+
+func (c *checkpoint) store(ctx processor.StepContext) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	maps.Copy(c.steps, ctx.Steps)
+	stepCtx := processor.NewContext()
+	stepCtx.Steps = c.steps
+
+	return storeContext(stepCtx, c.path)
+}
+
+// storeContext atomically replaces the context file so a crash never leaves a partial context behind.
+func storeContext(stepCtx processor.StepContext, contextPath string) error {
+	b, err := json.Marshal(stepCtx.ToV1Beta1())
 	if err != nil {
 		return err
 	}
 
+	tmp, err := os.CreateTemp(filepath.Dir(contextPath), filepath.Base(contextPath))
+	if err != nil {
+		return fmt.Errorf("failed to write step context: %w", err)
+	}
+
 	defer func() {
-		_ = f.Close()
+		_ = os.Remove(tmp.Name())
 	}()
 
-	b, err := json.Marshal(stepCtx.ToV1Beta1())
-	if err != nil {
-		return err
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write step context: %w", err)
 	}
 
-	_, err = f.Write(b)
-	return err
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write step context: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write step context: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), contextPath)
 }
 
 func (s *StepContext) recoverContext(stepCtx *processor.StepContext, contextDir string) error {
+	if contextDir == "" {
+		return nil
+	}
+
 	contextPath := filepath.Join(contextDir, "context.json")
 	if _, err := os.Stat(contextPath); err == nil {
 		f, err := os.Open(contextPath)
@@ -95,8 +156,35 @@ func (s *StepContext) recoverContext(stepCtx *processor.StepContext, contextDir
 			return err
 		}
 
+		if s.opts.From != "" {
+			if err := resumeFrom(vars, s.opts.From); err != nil {
+				return err
+			}
+		}
+
 		stepCtx.FromV1Beta1(vars)
+
+		// Containers of a previous execution are not running anymore
+		clear(stepCtx.Containers)
+	} else if s.opts.Resume != "" {
+		return fmt.Errorf("no step context found in %s", contextDir)
 	}
 
 	return nil
 }
+
+// resumeFrom drops all recorded steps which did not complete before the given step was started.
+// This includes the step itself and the steps containing it.
+func resumeFrom(vars *v1beta1.Context, stepName string) error {
+	from, ok := vars.Steps[stepName]
+	if !ok {
+		return fmt.Errorf("step %q was not executed in the resumed context", stepName)
+	}
+
+	startedAt := from.StartedAt
+	maps.DeleteFunc(vars.Steps, func(_ string, step *v1beta1.StepResult) bool {
+		return step.EndedAt.IsZero() || !step.EndedAt.Before(startedAt)
+	})
+
+	return nil
+}
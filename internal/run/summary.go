@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"path"
+	"slices"
 	"strings"
 	"text/tabwriter"
 
@@ -128,9 +129,29 @@ func (s *Summary) writePipelineErrorToStderr(err error, parents []error, rc *Run
 }
 
 func (s *Summary) writeSuccessToStderr(rc *RunContext) {
-	fmt.Fprintf(rc.Output.Stderr, "\nThe pipeline was successfully executed.\n\n")
+	// Allowed failures are listed separately as the pipeline succeeded nevertheless
+	var warnings []string
+	for name, step := range rc.Execution.StepContext.Steps {
+		if errors.Is(step.Error, processor.ErrAllowFailure) {
+			warnings = append(warnings, name)
+		}
+	}
+
+	slices.Sort(warnings)
+
+	if len(warnings) == 0 {
+		fmt.Fprintf(rc.Output.Stderr, "\nThe pipeline was successfully executed.\n\n")
+	} else {
+		fmt.Fprintf(rc.Output.Stderr, "\nThe pipeline was successfully executed with %d warnings.\n\n", len(warnings))
+	}
+
 	w := tabwriter.NewWriter(rc.Output.Stderr, 0, 0, 2, ' ', 0)
 	fmt.Fprintf(w, "%s\t%s\n", styles.Highlight.Render("Context path:"), rc.ContextDir.Path)
+
+	for _, name := range warnings {
+		fmt.Fprintf(w, "%s\t%s\n", styles.Highlight.Render(fmt.Sprintf("Warning %s:", name)), errors.Unwrap(rc.Execution.StepContext.Steps[name].Error).Error())
+	}
+
 	w.Flush()
 }
 
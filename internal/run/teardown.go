@@ -57,10 +57,11 @@ func (s *Teardown) Run(rc *RunContext, next Next) error {
 
 func (s *Teardown) runTeardown(rc *RunContext, wg *sync.WaitGroup) {
 	for fn := range rc.Teardown.Teardown {
+		wg.Add(1)
 		go func(fn processor.Teardown) {
-			wg.Add(1)
 			defer wg.Done()
 
+			// Teardown is not bound to the pipeline context, containers are removed even once the deadline is exceeded
 			teardownCtx := context.TODO()
 			if s.opts.GracePeriod > 0 {
 				ctx, cancel := context.WithTimeout(teardownCtx, s.opts.GracePeriod)
@@ -0,0 +1,282 @@
+package run
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raffis/rageta/internal/changes"
+	"github.com/raffis/rageta/internal/pipeline"
+	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/internal/styles"
+	"github.com/raffis/rageta/internal/tui"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+	"github.com/spf13/pflag"
+)
+
+type WatchOptions struct {
+	Watch    bool
+	Debounce time.Duration
+	Interval time.Duration
+}
+
+func NewWatchOptions() WatchOptions {
+	return WatchOptions{
+		Debounce: 300 * time.Millisecond,
+		Interval: 500 * time.Millisecond,
+	}
+}
+
+func (s *WatchOptions) BindFlags(flags *pflag.FlagSet) {
+	flags.BoolVarP(&s.Watch, "watch", "", s.Watch, "Keep running and execute the steps affected by changed files again. The files matched by step sources are watched, or the whole working directory if no step declares sources.")
+	flags.DurationVarP(&s.Debounce, "watch-debounce", "", s.Debounce, "Wait until no more files changed for the given duration before steps are executed again.")
+	flags.DurationVarP(&s.Interval, "watch-interval", "", s.Interval, "Interval in which watched files are checked for changes.")
+}
+
+func (s WatchOptions) Build() Step {
+	return &Watch{opts: s}
+}
+
+type Watch struct {
+	opts WatchOptions
+}
+
+type WatchContext struct {
+	// Teardown returns the teardown channel for a step, teardowns are deferred until the step is executed again
+	Teardown func(stepName string) chan processor.Teardown
+}
+
+func (s *Watch) Run(rc *RunContext, next Next) error {
+	if !s.opts.Watch || rc.DryRun.Plan != nil {
+		return next(rc)
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	patterns := sourcePatterns(rc.Provider.Pipeline)
+	watcher, err := changes.NewWatcher(dir, patterns, s.opts.Interval, s.opts.Debounce)
+	if err != nil {
+		return err
+	}
+
+	teardown := &watchTeardown{
+		teardown: rc.Teardown.Teardown,
+		channels: make(map[string]chan processor.Teardown),
+		steps:    make(map[string][]processor.Teardown),
+		sealed:   make(map[string]bool),
+	}
+
+	rc.Watch.Teardown = teardown.channel
+	defer teardown.close()
+
+	for generation := 1; ; generation++ {
+		s.notify(rc, tui.WatchMsg{Generation: generation, Status: tui.StepStatusRunning})
+
+		err = next(rc)
+		s.notify(rc, tui.WatchMsg{Generation: generation, Status: generationStatus(err), Error: err})
+
+		// Files written by the steps would trigger the next generation otherwise
+		if resetErr := watcher.Reset(); resetErr != nil {
+			return resetErr
+		}
+
+		files, invalid, waitErr := s.wait(rc, watcher, patterns)
+		if waitErr != nil {
+			// The watch ends once the pipeline is cancelled, the result of the last generation is returned
+			if rc.Context.Err() != nil {
+				return err
+			}
+
+			return waitErr
+		}
+
+		s.notify(rc, tui.WatchMsg{Generation: generation, Status: generationStatus(err), Error: err, Files: files, Steps: invalid})
+		teardown.flush(invalid)
+		teardown.seal()
+
+		stepCtx := processor.NewContext()
+		stepCtx.Recover(rc.Execution.StepContext.Steps)
+		maps.Copy(stepCtx.Containers, rc.Execution.StepContext.Containers)
+		for _, name := range invalid {
+			delete(stepCtx.Steps, name)
+			delete(stepCtx.Containers, name)
+		}
+
+		// All other steps are skipped as done, their outputs and service containers are reused
+		rc.Execution.StepContext = stepCtx
+		rc.StepContext.Resume = true
+
+		// The changed function of the cel env must use the same detector as the step sources
+		rc.Changes.Detector = changes.Always()
+		celEnv, envErr := NewCELEnv(rc.Changes.Detector)
+		if envErr != nil {
+			return envErr
+		}
+
+		rc.CEL.Env = celEnv
+	}
+}
+
+// wait blocks until watched files changed which affect any step.
+func (s *Watch) wait(rc *RunContext, watcher *changes.Watcher, patterns []string) ([]string, []string, error) {
+	for {
+		files, err := watcher.Wait(rc.Context)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Without any sources every step is affected
+		if len(patterns) == 0 {
+			var steps []string
+			for _, step := range rc.Provider.Pipeline.Steps {
+				steps = append(steps, step.Name)
+			}
+
+			return files, steps, nil
+		}
+
+		var affected []string
+		for _, step := range rc.Provider.Pipeline.Steps {
+			if slices.ContainsFunc(step.Sources, func(source v1beta1.Source) bool {
+				return slices.ContainsFunc(files, func(file string) bool {
+					return source.Match != "" && changes.Match(source.Match, file)
+				})
+			}) {
+				affected = append(affected, step.Name)
+			}
+		}
+
+		invalid, err := pipeline.Invalidate(rc.Provider.Pipeline, affected)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(invalid) > 0 {
+			return files, invalid, nil
+		}
+	}
+}
+
+// notify reports the generation to the terminal ui or prints it otherwise.
+func (s *Watch) notify(rc *RunContext, msg tui.WatchMsg) {
+	if rc.Output.UI != nil {
+		rc.Output.UI.Send(msg)
+		return
+	}
+
+	switch {
+	case len(msg.Files) > 0:
+		fmt.Fprintf(rc.Output.Stdout, "%s\n", styles.Highlight.Render(fmt.Sprintf("Changed %s, executing %s again", strings.Join(msg.Files, ", "), strings.Join(msg.Steps, ", "))))
+	case msg.Status == tui.StepStatusRunning:
+		fmt.Fprintf(rc.Output.Stdout, "%s\n", styles.Highlight.Render(fmt.Sprintf("Generation #%d started", msg.Generation)))
+	case msg.Error != nil:
+		fmt.Fprintf(rc.Output.Stdout, "%s\n", styles.Highlight.Render(fmt.Sprintf("Generation #%d failed: %s. Watching for changes...", msg.Generation, msg.Error)))
+	default:
+		fmt.Fprintf(rc.Output.Stdout, "%s\n", styles.Highlight.Render(fmt.Sprintf("Generation #%d succeeded. Watching for changes...", msg.Generation)))
+	}
+}
+
+func generationStatus(err error) tui.StepStatus {
+	if err != nil {
+		return tui.StepStatusFailed
+	}
+
+	return tui.StepStatusDone
+}
+
+// sourcePatterns returns the source patterns of all steps.
+func sourcePatterns(spec v1beta1.Pipeline) []string {
+	var patterns []string
+	for _, step := range spec.Steps {
+		for _, source := range step.Sources {
+			if source.Match != "" && !slices.Contains(patterns, source.Match) {
+				patterns = append(patterns, source.Match)
+			}
+		}
+	}
+
+	return patterns
+}
+
+// watchTeardown defers the teardown of steps until they are executed again or the watch ends.
+// This keeps services of steps which are not executed again running.
+type watchTeardown struct {
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	teardown chan processor.Teardown
+	channels map[string]chan processor.Teardown
+	steps    map[string][]processor.Teardown
+	sealed   map[string]bool
+}
+
+func (w *watchTeardown) channel(stepName string) chan processor.Teardown {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if ch, ok := w.channels[stepName]; ok {
+		return ch
+	}
+
+	ch := make(chan processor.Teardown)
+	w.channels[stepName] = ch
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		for fn := range ch {
+			w.mu.Lock()
+			// A skipped step reports the containers of its previous execution again
+			if !w.sealed[stepName] {
+				w.steps[stepName] = append(w.steps[stepName], fn)
+			}
+			w.mu.Unlock()
+		}
+	}()
+
+	return ch
+}
+
+// flush tears down the given steps.
+func (w *watchTeardown) flush(stepNames []string) {
+	w.mu.Lock()
+	var teardowns []processor.Teardown
+	for _, name := range stepNames {
+		teardowns = append(teardowns, w.steps[name]...)
+		delete(w.steps, name)
+		delete(w.sealed, name)
+	}
+	w.mu.Unlock()
+
+	for _, fn := range teardowns {
+		w.teardown <- fn
+	}
+}
+
+// seal ignores further teardowns of steps which are not executed again.
+func (w *watchTeardown) seal() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for name := range w.steps {
+		w.sealed[name] = true
+	}
+}
+
+// close tears down all steps.
+func (w *watchTeardown) close() {
+	w.mu.Lock()
+	for _, ch := range w.channels {
+		close(ch)
+	}
+	w.mu.Unlock()
+
+	w.wg.Wait()
+	w.flush(slices.Collect(maps.Keys(w.steps)))
+}
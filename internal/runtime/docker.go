@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/distribution/reference"
@@ -25,10 +26,13 @@ import (
 	"github.com/moby/moby/pkg/jsonmessage"
 	"github.com/moby/moby/registry"
 	"github.com/moby/term"
+	"github.com/muesli/cancelreader"
 	"golang.org/x/sync/errgroup"
 	"k8s.io/utils/strings/slices"
 )
 
+const debugShell = "/bin/sh"
+
 type dockerOption func(*docker)
 
 func WithContext(ctx context.Context) func(*docker) {
@@ -471,3 +475,134 @@ func (d *docker) getRestartPolicy(policy RestartPolicy) dockercontainer.RestartP
 		}
 	}
 }
+
+// DebugPod commits the filesystem of the pods container to a temporary image and starts an interactive shell from it.
+// The shell is started with the same environment, mounts and working directory as the container.
+func (d *docker) DebugPod(ctx context.Context, pod *Pod, stdin io.Reader, stdout io.Writer) error {
+	if len(pod.Status.Containers) == 0 {
+		return errors.New("pod has no container to debug")
+	}
+
+	containerID := pod.Status.Containers[0].ContainerID
+	spec, err := d.client.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	image, err := d.client.ContainerCommit(ctx, containerID, dockercontainer.CommitOptions{
+		Comment: "rageta debug shell",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to commit container: %w", err)
+	}
+
+	defer func() {
+		_, _ = d.client.ImageRemove(context.Background(), image.ID, imagetypes.RemoveOptions{
+			Force:         true,
+			PruneChildren: true,
+		})
+	}()
+
+	containerConfig := dockercontainer.Config{
+		Image:        image.ID,
+		Entrypoint:   strslice.StrSlice{debugShell},
+		Env:          spec.Config.Env,
+		WorkingDir:   spec.Config.WorkingDir,
+		User:         spec.Config.User,
+		Tty:          true,
+		OpenStdin:    true,
+		StdinOnce:    true,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+	}
+
+	hostConfig := dockercontainer.HostConfig{
+		Mounts:      spec.HostConfig.Mounts,
+		NetworkMode: spec.HostConfig.NetworkMode,
+	}
+
+	netConfig := network.NetworkingConfig{
+		EndpointsConfig: make(map[string]*network.EndpointSettings),
+	}
+
+	for k := range spec.NetworkSettings.Networks {
+		netConfig.EndpointsConfig[k] = &network.EndpointSettings{
+			NetworkID: k,
+		}
+	}
+
+	cont, err := d.client.ContainerCreate(ctx, &containerConfig, &hostConfig, &netConfig, nil, fmt.Sprintf("%s-debug", strings.TrimPrefix(spec.Name, "/")))
+	if err != nil {
+		return fmt.Errorf("failed to create debug container: %w", err)
+	}
+
+	defer func() {
+		_ = d.client.ContainerRemove(context.Background(), cont.ID, dockercontainer.RemoveOptions{
+			Force: true,
+		})
+	}()
+
+	waitC, errC := d.client.ContainerWait(ctx, cont.ID, dockercontainer.WaitConditionNextExit)
+	streams, err := d.client.ContainerAttach(ctx, cont.ID, dockercontainer.AttachOptions{
+		Stdin:  true,
+		Stdout: true,
+		Stderr: true,
+		Stream: true,
+	})
+	if err != nil {
+		return fmt.Errorf("container attach failed: %w", err)
+	}
+
+	defer streams.Close()
+
+	// The input is cancelled once the shell exited, otherwise the next read from stdin would be lost
+	in, err := cancelreader.NewReader(stdin)
+	if err != nil {
+		return err
+	}
+
+	defer in.Cancel()
+
+	fd, isTerm := term.GetFdInfo(stdin)
+	if isTerm {
+		state, err := term.SetRawTerminal(fd)
+		if err != nil {
+			return fmt.Errorf("failed to set terminal to raw mode: %w", err)
+		}
+
+		defer func() {
+			_ = term.RestoreTerminal(fd, state)
+		}()
+	}
+
+	if err := d.client.ContainerStart(ctx, cont.ID, dockercontainer.StartOptions{}); err != nil {
+		return fmt.Errorf("failed to start debug container: %w", err)
+	}
+
+	if isTerm {
+		if size, err := term.GetWinsize(fd); err == nil {
+			_ = d.client.ContainerResize(ctx, cont.ID, dockercontainer.ResizeOptions{
+				Height: uint(size.Height),
+				Width:  uint(size.Width),
+			})
+		}
+	}
+
+	go func() {
+		_, _ = io.Copy(stdout, streams.Reader)
+	}()
+
+	go func() {
+		_, _ = io.Copy(streams.Conn, in)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-errC:
+		return fmt.Errorf("failed to wait for debug container: %w", err)
+	case <-waitC:
+		return nil
+	}
+}
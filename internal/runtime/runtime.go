@@ -11,6 +11,11 @@ type Interface interface {
 	DeletePod(ctx context.Context, pod *Pod, timeout time.Duration) error
 }
 
+// Debugger is implemented by drivers which can start an interactive shell in the filesystem of a terminated container.
+type Debugger interface {
+	DebugPod(ctx context.Context, pod *Pod, stdin io.Reader, stdout io.Writer) error
+}
+
 type Await interface {
 	Wait(ctx context.Context) error
 }
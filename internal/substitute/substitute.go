@@ -75,6 +75,21 @@ func Substitute(index Indexable, substitute ...any) error {
 	return nil
 }
 
+// Expressions returns the variable names referenced by $(...) expressions in the given string.
+// Escaped expressions ($$(...)) are ignored.
+func Expressions(s string) []string {
+	var names []string
+	for _, parts := range substituteExpression.FindAllStringSubmatch(s, -1) {
+		if parts[1] == `$$` {
+			continue
+		}
+
+		names = append(names, parts[2])
+	}
+
+	return names
+}
+
 func substParam(param *v1beta1.Param, vars map[string]string) (*v1beta1.ParamValue, error) {
 	switch param.Value.Type {
 	case v1beta1.ParamTypeString:
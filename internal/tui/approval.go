@@ -0,0 +1,82 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+	"github.com/raffis/rageta/internal/processor"
+	"github.com/raffis/rageta/pkg/apis/core/v1beta1"
+)
+
+const (
+	KeyApprove = "y"
+	KeyReject  = "n"
+)
+
+// ApprovalMsg requests a decision for an approval step, the decision is sent to Reply
+type ApprovalMsg struct {
+	Request processor.ApprovalRequest
+	Reply   chan<- v1beta1.ApprovalDecision
+}
+
+// ApprovalDismissMsg removes a pending approval which is not awaited anymore
+type ApprovalDismissMsg struct {
+	StepName string
+}
+
+// handleApproval queues an approval request
+func (m *UI) handleApproval(msg ApprovalMsg) {
+	m.approvals = append(m.approvals, msg)
+}
+
+// handleApprovalDismiss removes a pending approval request
+func (m *UI) handleApprovalDismiss(msg ApprovalDismissMsg) {
+	for i, approval := range m.approvals {
+		if approval.Request.StepName == msg.StepName {
+			m.approvals = append(m.approvals[:i:i], m.approvals[i+1:]...)
+			return
+		}
+	}
+}
+
+// handleApprovalKeys decides the first pending approval, it returns false if the key is not an approval key
+func (m *UI) handleApprovalKeys(msg tea.KeyPressMsg) bool {
+	var decision v1beta1.ApprovalDecision
+	switch msg.String() {
+	case KeyApprove:
+		decision = v1beta1.ApprovalDecisionApprove
+	case KeyReject:
+		decision = v1beta1.ApprovalDecisionReject
+	default:
+		return false
+	}
+
+	m.approvals[0].Reply <- decision
+	m.approvals = m.approvals[1:]
+	return true
+}
+
+// renderApproval draws the modal of the first pending approval on top of the given content
+func (m UI) renderApproval(content string) string {
+	approval := m.approvals[0]
+
+	body := approval.Request.Message
+	if body != "" {
+		body += "\n\n"
+	}
+
+	body += fmt.Sprintf("[%s] approve  [%s] reject", KeyApprove, KeyReject)
+	if len(m.approvals) > 1 {
+		body += fmt.Sprintf("\n%d more pending", len(m.approvals)-1)
+	}
+
+	modal := NewModal(fmt.Sprintf("Approval required: %s", approval.Request.StepName), body).Render()
+	x := max(0, (m.width-lipgloss.Width(modal))/2)
+	y := max(0, (m.height-lipgloss.Height(modal))/2)
+
+	return lipgloss.NewCanvas(m.width, m.height).Compose(lipgloss.NewCompositor(
+		lipgloss.NewLayer(content),
+		lipgloss.NewLayer(modal).X(x).Y(y).Z(1),
+	)).Render()
+}
@@ -17,6 +17,16 @@ const (
 type Modal struct {
 	windowWidth  int
 	windowHeight int
+	title        string
+	content      string
+}
+
+// NewModal creates a modal displaying the given title and content
+func NewModal(title, content string) *Modal {
+	return &Modal{
+		title:   title,
+		content: content,
+	}
 }
 
 // Init initializes the Modal on program load
@@ -47,13 +57,22 @@ func (m *Modal) handleWindowResize(msg tea.WindowSizeMsg) {
 // View renders the modal with appropriate styling
 // It implements part of the tea.Model interface
 func (m *Modal) View() tea.View {
-	modalStyle := m.createModalStyle()
-	titleStyle := m.createTitleStyle()
+	return tea.NewView(m.Render())
+}
 
-	title := titleStyle.Render(ModalTitle)
-	layout := lipgloss.JoinVertical(lipgloss.Left, title, ModalContent)
+// Render returns the styled modal content, it is used to draw the modal as an overlay
+func (m *Modal) Render() string {
+	return m.createModalStyle().Render(m.render(m.createTitleStyle()))
+}
+
+// render joins the title and the content of the modal
+func (m *Modal) render(titleStyle lipgloss.Style) string {
+	title, content := m.title, m.content
+	if title == "" && content == "" {
+		title, content = ModalTitle, ModalContent
+	}
 
-	return tea.NewView(modalStyle.Render(layout))
+	return lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render(title), content)
 }
 
 // createModalStyle creates the main modal styling
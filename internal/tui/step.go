@@ -83,7 +83,7 @@ func (t StepMsg) WithStatus(status StepStatus) StepMsg {
 		t.started = time.Now()
 	}
 
-	if t.finished.IsZero() && status > StepStatusRunning {
+	if t.finished.IsZero() && status > StepStatusRunning && status != StepStatusBlocked {
 		t.finished = time.Now()
 	}
 
@@ -210,6 +210,9 @@ const (
 	StepStatusFailed
 	StepStatusDone
 	StepStatusSkipped
+	StepStatusBlocked
+	StepStatusTimeout
+	StepStatusWarning
 )
 
 // Step status string representations
@@ -219,6 +222,9 @@ var stepStatusStrings = []string{
 	"failed",
 	"done",
 	"skipped",
+	"blocked",
+	"timeout",
+	"warning",
 }
 
 // String returns the string representation of the step status
@@ -242,6 +248,12 @@ func (e StepStatus) Render() string {
 		return stepWaitingStyle.Render("◎")
 	case StepStatusSkipped:
 		return stepWarningStyle.Render("⚠")
+	case StepStatusBlocked:
+		return stepWaitingStyle.Render("⏸")
+	case StepStatusTimeout:
+		return stepFailedStyle.Render("⏱")
+	case StepStatusWarning:
+		return stepWarningStyle.Render("▲")
 	default:
 		return stepWaitingStyle.Render("?")
 	}
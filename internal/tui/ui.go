@@ -54,6 +54,8 @@ type UI struct {
 	exitErr      error
 	activePanel  Panel
 	lastSelected list.Item
+	approvals    []ApprovalMsg
+	generation   int
 }
 
 type TickMsg time.Time
@@ -174,6 +176,8 @@ func (m *UI) renderStatus() string {
 		return pipelineOkStyle.Render("SUCCESS")
 	case StepStatusFailed:
 		return pipelineFailedStyle.Render("FAILED")
+	case StepStatusTimeout:
+		return pipelineFailedStyle.Render("TIMED OUT")
 	case StepStatusWaiting:
 		return pipelineWaitingStyle.Render("INITIALIZING")
 	case StepStatusRunning:
@@ -211,6 +215,12 @@ func (m UI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, m.handleWindowResize(msg)...)
 	case TickMsg:
 		cmds = append(cmds, m.handleTick(msg)...)
+	case ApprovalMsg:
+		m.handleApproval(msg)
+	case ApprovalDismissMsg:
+		m.handleApprovalDismiss(msg)
+	case WatchMsg:
+		m.handleWatch(msg)
 	}
 
 	m.updateLastSelected()
@@ -226,11 +236,11 @@ func (m *UI) handlePipelineDone(msg PipelineDoneMsg) []tea.Cmd {
 	m.status = msg.Status
 	m.exitErr = msg.Error
 
-	if msg.Status == StepStatusFailed {
+	if msg.Status == StepStatusFailed || msg.Status == StepStatusTimeout {
 		items := slices.Clone(m.list.Items())
 		for i, listItem := range items {
-			if item, ok := listItem.(StepMsg); ok && item.Status == StepStatusRunning {
-				items[i] = item.WithStatus(StepStatusFailed)
+			if item, ok := listItem.(StepMsg); ok && (item.Status == StepStatusRunning || item.Status == StepStatusBlocked) {
+				items[i] = item.WithStatus(msg.Status)
 			}
 		}
 		m.list.SetItems(items)
@@ -307,6 +317,10 @@ func (m *UI) handleMouseMessage(msg tea.MouseMsg) tea.Cmd {
 
 // handleKeyMessage handles keyboard input
 func (m UI) handleKeyMessage(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if len(m.approvals) > 0 && m.handleApprovalKeys(msg) {
+		return m, nil
+	}
+
 	switch msg.String() {
 	case KeyQuit:
 	case KeyQ:
@@ -438,6 +452,11 @@ func (m UI) View() tea.View {
 	} else {
 		content = m.renderMainLayout()
 	}
+
+	if len(m.approvals) > 0 && m.width > 0 && m.height > 0 {
+		content = m.renderApproval(content)
+	}
+
 	v := tea.NewView(content)
 	v.AltScreen = true
 	v.MouseMode = tea.MouseModeCellMotion
@@ -609,7 +628,7 @@ func (m UI) buildPagerContent(step StepMsg) []string {
 
 // renderBottomPanel renders the bottom status panel
 func (m UI) renderBottomPanel() string {
-	status := m.renderStatus()
+	status := m.renderStatus() + m.renderGeneration()
 	scrollPercentage := scrollPercentageStyle.Render(
 		fmt.Sprintf("%3.f%%", m.lastSelected.(StepMsg).viewport.ScrollPercent()*100))
 
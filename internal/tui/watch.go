@@ -0,0 +1,35 @@
+package tui
+
+import (
+	"fmt"
+)
+
+// WatchMsg reports the state of a run generation in watch mode
+type WatchMsg struct {
+	Generation int
+	Status     StepStatus
+	Error      error
+	Files      []string
+	Steps      []string
+}
+
+// handleWatch updates the pipeline status to the current generation
+func (m *UI) handleWatch(msg WatchMsg) {
+	m.generation = msg.Generation
+	m.status = msg.Status
+	m.exitErr = msg.Error
+}
+
+// renderGeneration renders the current generation in watch mode
+func (m *UI) renderGeneration() string {
+	if m.generation == 0 {
+		return ""
+	}
+
+	generation := fmt.Sprintf("#%d", m.generation)
+	if m.status != StepStatusRunning {
+		generation += " WATCHING"
+	}
+
+	return pipelineWaitingStyle.Render(generation)
+}
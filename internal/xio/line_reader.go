@@ -0,0 +1,54 @@
+package xio
+
+import (
+	"bufio"
+	"context"
+	"io"
+
+	"github.com/muesli/cancelreader"
+)
+
+// ReadLines reads lines from r until the returned cancel func is called or ctx is done.
+// Cancelling stops the read so no further input is consumed, e.g. once an answer was read from stdin.
+// The returned cancel func must be called to release the reader.
+func ReadLines(ctx context.Context, r io.Reader) (<-chan string, func(), error) {
+	reader, err := cancelreader.NewReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	lines := make(chan string)
+	scanned := make(chan struct{})
+	closed := make(chan struct{})
+
+	go func() {
+		defer close(closed)
+		<-ctx.Done()
+
+		// The reader must not be closed while it is read from, a successful cancel guarantees the read returns
+		if reader.Cancel() {
+			<-scanned
+		}
+
+		_ = reader.Close()
+	}()
+
+	go func() {
+		defer close(scanned)
+		defer close(lines)
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return lines, func() {
+		cancel()
+		<-closed
+	}, nil
+}
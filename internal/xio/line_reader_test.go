@@ -0,0 +1,78 @@
+package xio
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadLines(t *testing.T) {
+	t.Run("reads lines until eof", func(t *testing.T) {
+		r, w := io.Pipe()
+		lines, cancel, err := ReadLines(context.Background(), r)
+		require.NoError(t, err)
+		defer cancel()
+
+		go func() {
+			_, _ = w.Write([]byte("first\nsecond\n"))
+			_ = w.Close()
+		}()
+
+		var got []string
+		for line := range lines {
+			got = append(got, line)
+		}
+
+		assert.Equal(t, []string{"first", "second"}, got)
+	})
+
+	t.Run("cancel stops reading", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		defer w.Close()
+		defer r.Close()
+
+		lines, cancel, err := ReadLines(context.Background(), r)
+		require.NoError(t, err)
+
+		_, err = w.Write([]byte("answer\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "answer", <-lines)
+
+		cancel()
+
+		select {
+		case _, ok := <-lines:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("lines not closed after cancel")
+		}
+	})
+	t.Run("cancel releases the reader", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		defer w.Close()
+		defer r.Close()
+
+		fds, err := os.ReadDir("/proc/self/fd")
+		if err != nil {
+			t.Skip("open file descriptors are not known")
+		}
+
+		before := len(fds)
+		for range 10 {
+			_, cancel, err := ReadLines(context.Background(), r)
+			require.NoError(t, err)
+			cancel()
+		}
+
+		fds, err = os.ReadDir("/proc/self/fd")
+		require.NoError(t, err)
+		assert.Equal(t, before, len(fds))
+	})
+}
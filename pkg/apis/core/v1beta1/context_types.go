@@ -7,11 +7,14 @@ import (
 )
 
 type StepResult struct {
-	Outputs   map[string]ParamValue `cel:"outputs"`
-	TmpDir    string                `cel:"tmpDir"`
-	Error     string                `cel:"error"`
-	StartedAt time.Time             `cel:"startedAt"`
-	EndedAt   time.Time             `cel:"endedAt"`
+	Outputs map[string]ParamValue `cel:"outputs"`
+	// Envs and Secrets are the variables available after the step completed, including the ones it exported.
+	Envs      map[string]string `cel:"envs"`
+	Secrets   map[string]string `cel:"secrets"`
+	TmpDir    string            `cel:"tmpDir"`
+	Error     string            `cel:"error"`
+	StartedAt time.Time         `cel:"startedAt"`
+	EndedAt   time.Time         `cel:"endedAt"`
 }
 
 type ContainerStatus struct {
@@ -27,6 +30,17 @@ type Output struct {
 	Path string `cel:"path"`
 }
 
+type Attempt struct {
+	Number   int    `cel:"number"`
+	Error    string `cel:"error"`
+	ExitCode int    `cel:"exitCode"`
+}
+
+type Loop struct {
+	Item  string `cel:"item"`
+	Index int    `cel:"index"`
+}
+
 type Context struct {
 	Inputs     map[string]ParamValue       `cel:"inputs"`
 	Envs       map[string]string           `cel:"envs"`
@@ -42,6 +56,10 @@ type Context struct {
 	Arch       string                      `cel:"arch"`
 	Uid        string                      `cel:"uid"`
 	Guid       string                      `cel:"guid"`
+	Loop       *Loop                       `cel:"loop"`
+	Attempt    *Attempt                    `cel:"attempt"`
+	// Deadline is the remaining time until the pipeline deadline, it is not set without a deadline.
+	Deadline time.Duration `cel:"deadline"`
 }
 
 func (v *Context) Index() map[string]string {
@@ -80,6 +98,19 @@ func (v *Context) Index() map[string]string {
 		vars[fmt.Sprintf("context.matrix.%s", k)] = v
 	}
 
+	if v.Loop != nil {
+		vars["context.loop.item"] = v.Loop.Item
+		vars["context.loop.index"] = fmt.Sprintf("%d", v.Loop.Index)
+	}
+
+	if v.Attempt != nil {
+		vars["context.attempt.number"] = fmt.Sprintf("%d", v.Attempt.Number)
+	}
+
+	if v.Deadline != 0 {
+		vars["context.deadline"] = fmt.Sprintf("%d", int64(v.Deadline.Seconds()))
+	}
+
 	for k, v := range v.Containers {
 		vars[fmt.Sprintf("context.containers.%s.containerID", k)] = v.ContainerID
 		vars[fmt.Sprintf("context.containers.%s.containerIP", k)] = v.ContainerIP
@@ -95,6 +126,12 @@ func (v *Context) Index() map[string]string {
 		vars[fmt.Sprintf("context.steps.%s.error", k)] = v.Error
 		vars[fmt.Sprintf("context.steps.%s.startedAt", k)] = fmt.Sprintf("%d", v.StartedAt.Unix())
 		vars[fmt.Sprintf("context.steps.%s.endedAt", k)] = fmt.Sprintf("%d", v.EndedAt.Unix())
+		for name, value := range v.Envs {
+			vars[fmt.Sprintf("context.steps.%s.envs.%s", k, name)] = value
+		}
+		for name, value := range v.Secrets {
+			vars[fmt.Sprintf("context.steps.%s.secrets.%s", k, name)] = value
+		}
 		for outputName, v := range v.Outputs {
 			switch v.Type {
 			case ParamTypeString:
@@ -78,8 +78,20 @@ type StepOutputParam struct {
 	// Name declares the name by which a parameter is referenced.
 	Name string        `json:"name"`
 	Step StepReference `json:"step"`
+	// Type of the output, a param value by default.
+	// Outputs of type pipeline hold a pipeline manifest which can be executed by a subsequent inherit step.
+	// +optional
+	Type StepOutputType `json:"type,omitempty"`
 }
 
+// StepOutputType indicates what a step writes to an output.
+type StepOutputType string
+
+const (
+	StepOutputTypeParam    StepOutputType = "param"
+	StepOutputTypePipeline StepOutputType = "pipeline"
+)
+
 // PropertySpec defines the struct for object keys
 type PropertySpec struct {
 	Type ParamType `json:"type,omitempty"`
@@ -15,7 +15,10 @@ limitations under the License.
 package v1beta1
 
 import (
+	"encoding/json"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -34,6 +37,27 @@ type PipelineSpec struct {
 	Inputs           InputParams  `json:"inputs,omitempty"`
 	Outputs          OutputParams `json:"outputs,omitempty"`
 	Steps            []Step       `json:"steps,omitempty"`
+	// Extends references a pipeline whose steps, inputs and outputs are inherited.
+	// Steps with the same name as an inherited step are merged into it.
+	Extends string `json:"extends,omitempty"`
+	// Patches are applied to the named steps once the pipeline is merged with the extended one.
+	Patches []StepPatch `json:"patches,omitempty"`
+	// Timeout is the deadline for the whole pipeline, it applies to every step.
+	// +optional
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+}
+
+type StepPatch struct {
+	Step  StepReference        `json:"step"`
+	Patch []JSONPatchOperation `json:"patch,omitempty"`
+}
+
+// JSONPatchOperation is a RFC 6902 JSON patch operation.
+type JSONPatchOperation struct {
+	Op    string                `json:"op"`
+	Path  string                `json:"path"`
+	From  string                `json:"from,omitempty"`
+	Value *runtime.RawExtension `json:"value,omitempty"`
 }
 
 func (p Pipeline) SetDefaults() {
@@ -43,12 +67,16 @@ func (p Pipeline) SetDefaults() {
 }
 
 type StepOptions struct {
-	Extends      *StepReference    `json:"extends,omitempty"`
-	If           []IfCondition     `json:"if,omitempty"`
-	Expose       bool              `json:"expose,omitempty"`
-	Inputs       []InputParam      `json:"inputs,omitempty"`
-	Timeout      metav1.Duration   `json:"timeout"`
-	AllowFailure bool              `json:"allowFailure,omitempty"`
+	Extends *StepReference  `json:"extends,omitempty"`
+	Uses    string          `json:"uses,omitempty"`
+	With    []Param         `json:"with,omitempty"`
+	If      []IfCondition   `json:"if,omitempty"`
+	Expose  bool            `json:"expose,omitempty"`
+	Inputs  []InputParam    `json:"inputs,omitempty"`
+	Timeout metav1.Duration `json:"timeout"`
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	AllowFailure *AllowFailure     `json:"allowFailure,omitempty"`
 	Template     *Template         `json:"template,omitempty"`
 	Matrix       *Matrix           `json:"matrix,omitempty"`
 	Outputs      []StepOutputParam `json:"outputs,omitempty"`
@@ -60,6 +88,25 @@ type StepOptions struct {
 	Secrets      []SecretVar       `json:"secrets,omitempty"`
 	Env          []EnvVar          `json:"env,omitempty"`
 	Tags         []Tag             `json:"tags,omitempty"`
+	// Lock is the name of a lock held while the step is executed.
+	// Steps with the same lock are not executed at the same time, across all rageta processes on the host.
+	// +optional
+	Lock string `json:"lock,omitempty"`
+	// Semaphores acquired before the step is executed.
+	// +optional
+	Semaphores []SemaphoreRef `json:"semaphores,omitempty"`
+	// Breakpoint pauses the pipeline before the step is executed, it is ignored if stdin is not a terminal.
+	// +optional
+	Breakpoint bool `json:"breakpoint,omitempty"`
+}
+
+// SemaphoreRef acquires weight units of the named semaphore which allows up to capacity units to be held at the same time.
+type SemaphoreRef struct {
+	Name string `json:"name"`
+	// +optional
+	Capacity int `json:"capacity,omitempty"`
+	// +optional
+	Weight int `json:"weight,omitempty"`
 }
 
 type Tag struct {
@@ -80,6 +127,9 @@ type EnvVar struct {
 
 type IfCondition struct {
 	CelExpression *string `json:"celExpression,omitempty"`
+	// Changed is true if any file matching one of the gitignore style patterns changed.
+	// +optional
+	Changed []string `json:"changed,omitempty"`
 }
 
 type Matrix struct {
@@ -104,6 +154,63 @@ type Retry struct {
 	Exponential metav1.Duration `json:"exponential"`
 	Constant    metav1.Duration `json:"constant"`
 	MaxRetries  int             `json:"maxRetries,omitempty"`
+	// Jitter adds a random duration of up to the given value to each backoff.
+	// +optional
+	Jitter metav1.Duration `json:"jitter,omitempty"`
+	// MaxDuration limits the total time spent on retrying the step.
+	// +optional
+	MaxDuration metav1.Duration `json:"maxDuration,omitempty"`
+	// RetryOn restricts the failures which are retried, every failure is retried if unset.
+	// +optional
+	RetryOn *RetryOn `json:"retryOn,omitempty"`
+	// ResetBetweenAttempts discards outputs and env variables of a failed attempt before the next one.
+	// +optional
+	ResetBetweenAttempts bool `json:"resetBetweenAttempts,omitempty"`
+}
+
+// RetryOn retries a failed attempt if any of the conditions matches.
+type RetryOn struct {
+	// ExitCodes of the step container which are retried.
+	// +optional
+	ExitCodes []int `json:"exitCodes,omitempty"`
+	// CelExpression is evaluated after a failed attempt, the attempt is available as context.attempt.
+	// +optional
+	CelExpression string `json:"celExpression,omitempty"`
+}
+
+// AllowFailure continues the pipeline with a warning if the step fails.
+// It is either a bool allowing every failure or an object restricting the allowed failures,
+// a failure is allowed if any of the conditions matches.
+type AllowFailure struct {
+	// Always allows every failure, it is set by the bool form.
+	Always bool `json:"-"`
+	// ExitCodes of the step container which are allowed.
+	// +optional
+	ExitCodes []int `json:"exitCodes,omitempty"`
+	// CelExpression is evaluated after a failure, the failed attempt is available as context.attempt.
+	// +optional
+	CelExpression string `json:"celExpression,omitempty"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaller interface.
+func (a *AllowFailure) UnmarshalJSON(value []byte) error {
+	if len(value) > 0 && value[0] == '{' {
+		type allowFailure AllowFailure
+		return json.Unmarshal(value, (*allowFailure)(a))
+	}
+
+	*a = AllowFailure{}
+	return json.Unmarshal(value, &a.Always)
+}
+
+// MarshalJSON implements the json.Marshaller interface.
+func (a AllowFailure) MarshalJSON() ([]byte, error) {
+	if len(a.ExitCodes) == 0 && a.CelExpression == "" {
+		return json.Marshal(a.Always)
+	}
+
+	type allowFailure AllowFailure
+	return json.Marshal(allowFailure(a))
 }
 
 type Source struct {
@@ -124,12 +231,57 @@ type Step struct {
 	Concurrent  *ConcurrentStep `json:"concurrent,omitempty"`
 	Run         *RunStep        `json:"run,omitempty"`
 	Inherit     *InheritStep    `json:"inherit,omitempty"`
+	Approval    *ApprovalStep   `json:"approval,omitempty"`
+	Switch      *SwitchStep     `json:"switch,omitempty"`
+	ForEach     *ForEachStep    `json:"forEach,omitempty"`
 }
 
 type AndStep struct {
 	Refs []StepReference `json:"refs,omitempty"`
 }
 
+// SwitchStep executes the refs of the first case whose expression evaluates to true.
+// The refs of the default are executed if no case matches.
+type SwitchStep struct {
+	Cases   []SwitchCase   `json:"cases,omitempty"`
+	Default *SwitchDefault `json:"default,omitempty"`
+}
+
+type SwitchCase struct {
+	// Name of the case, the expression is used to describe the case if not set.
+	// +optional
+	Name          string          `json:"name,omitempty"`
+	CelExpression string          `json:"celExpression,omitempty"`
+	Refs          []StepReference `json:"refs,omitempty"`
+}
+
+type SwitchDefault struct {
+	Refs []StepReference `json:"refs,omitempty"`
+}
+
+// ForEachStep executes the refs sequentially once per item.
+// Without items the refs are executed until the until or while condition stops the loop.
+type ForEachStep struct {
+	// Items to iterate, a string value is decoded as json array after substitution.
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	Items *ParamValue     `json:"items,omitempty"`
+	Refs  []StepReference `json:"refs,omitempty"`
+	// Until is evaluated after each iteration, the loop stops once it evaluates to true.
+	// +optional
+	Until string `json:"until,omitempty"`
+	// While is evaluated before each iteration, the loop stops once it evaluates to false.
+	// +optional
+	While string `json:"while,omitempty"`
+	// MaxIterations fails the step if the loop does not stop within the given iterations, defaults to 100.
+	// +optional
+	MaxIterations int `json:"maxIterations,omitempty"`
+	// Interval to wait between iterations.
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+}
+
 type StepReference struct {
 	Name string `json:"name,omitempty"`
 }
@@ -190,6 +342,34 @@ type InheritStep struct {
 	Pipeline   string  `json:"pipeline,omitempty"`
 	Entrypoint string  `json:"entrypoint,omitempty"`
 	Inputs     []Param `json:"inputs,omitempty"`
+	// Generated executes the pipeline written to a pipeline output of a previous step instead of a pipeline reference.
+	// +optional
+	Generated *GeneratedPipeline `json:"generated,omitempty"`
+}
+
+type ApprovalStep struct {
+	// Message is shown to the approver.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Timeout after which the default outcome applies, the step waits for a decision indefinitely if unset.
+	// +optional
+	Timeout metav1.Duration `json:"timeout,omitempty"`
+	// Default is the outcome once the timeout expires, approvals are rejected by default.
+	// +optional
+	Default ApprovalDecision `json:"default,omitempty"`
+}
+
+// ApprovalDecision is the outcome of an approval step.
+type ApprovalDecision string
+
+const (
+	ApprovalDecisionApprove ApprovalDecision = "approve"
+	ApprovalDecisionReject  ApprovalDecision = "reject"
+)
+
+type GeneratedPipeline struct {
+	Step   StepReference `json:"step"`
+	Output string        `json:"output"`
 }
 
 type Streams struct {
@@ -0,0 +1,48 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// StepPackage is a single reusable step which is referenced by steps using `uses`.
+type StepPackage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	StepPackageSpec `json:",inline"`
+}
+
+type StepPackageSpec struct {
+	ShortDescription string            `json:"shortDescription,omitempty"`
+	LongDescription  string            `json:"longDescription,omitempty"`
+	Inputs           InputParams       `json:"inputs,omitempty"`
+	Outputs          []StepOutputParam `json:"outputs,omitempty"`
+	Step             Step              `json:"step"`
+}
+
+// +kubebuilder:object:root=true
+// StepPackageList contains a list of StepPackage
+type StepPackageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []StepPackage `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&StepPackage{}, &StepPackageList{})
+}
@@ -23,6 +23,26 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllowFailure) DeepCopyInto(out *AllowFailure) {
+	*out = *in
+	if in.ExitCodes != nil {
+		in, out := &in.ExitCodes, &out.ExitCodes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowFailure.
+func (in *AllowFailure) DeepCopy() *AllowFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(AllowFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AndStep) DeepCopyInto(out *AndStep) {
 	*out = *in
@@ -43,6 +63,37 @@ func (in *AndStep) DeepCopy() *AndStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApprovalStep) DeepCopyInto(out *ApprovalStep) {
+	*out = *in
+	out.Timeout = in.Timeout
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalStep.
+func (in *ApprovalStep) DeepCopy() *ApprovalStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Attempt) DeepCopyInto(out *Attempt) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Attempt.
+func (in *Attempt) DeepCopy() *Attempt {
+	if in == nil {
+		return nil
+	}
+	out := new(Attempt)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConcurrentStep) DeepCopyInto(out *ConcurrentStep) {
 	*out = *in
@@ -197,6 +248,16 @@ func (in *Context) DeepCopyInto(out *Context) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Loop != nil {
+		in, out := &in.Loop, &out.Loop
+		*out = new(Loop)
+		**out = **in
+	}
+	if in.Attempt != nil {
+		in, out := &in.Attempt, &out.Attempt
+		*out = new(Attempt)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Context.
@@ -229,6 +290,32 @@ func (in *EnvVar) DeepCopy() *EnvVar {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ForEachStep) DeepCopyInto(out *ForEachStep) {
+	*out = *in
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = new(ParamValue)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Refs != nil {
+		in, out := &in.Refs, &out.Refs
+		*out = make([]StepReference, len(*in))
+		copy(*out, *in)
+	}
+	out.Interval = in.Interval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ForEachStep.
+func (in *ForEachStep) DeepCopy() *ForEachStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ForEachStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Generate) DeepCopyInto(out *Generate) {
 	*out = *in
@@ -244,6 +331,22 @@ func (in *Generate) DeepCopy() *Generate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratedPipeline) DeepCopyInto(out *GeneratedPipeline) {
+	*out = *in
+	out.Step = in.Step
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratedPipeline.
+func (in *GeneratedPipeline) DeepCopy() *GeneratedPipeline {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratedPipeline)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IfCondition) DeepCopyInto(out *IfCondition) {
 	*out = *in
@@ -252,6 +355,11 @@ func (in *IfCondition) DeepCopyInto(out *IfCondition) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Changed != nil {
+		in, out := &in.Changed, &out.Changed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IfCondition.
@@ -297,6 +405,11 @@ func (in *InheritStep) DeepCopyInto(out *InheritStep) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Generated != nil {
+		in, out := &in.Generated, &out.Generated
+		*out = new(GeneratedPipeline)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InheritStep.
@@ -367,6 +480,41 @@ func (in InputParams) DeepCopy() InputParams {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JSONPatchOperation) DeepCopyInto(out *JSONPatchOperation) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONPatchOperation.
+func (in *JSONPatchOperation) DeepCopy() *JSONPatchOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONPatchOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Loop) DeepCopyInto(out *Loop) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Loop.
+func (in *Loop) DeepCopy() *Loop {
+	if in == nil {
+		return nil
+	}
+	out := new(Loop)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Matrix) DeepCopyInto(out *Matrix) {
 	*out = *in
@@ -718,6 +866,14 @@ func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]StepPatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Timeout = in.Timeout
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSpec.
@@ -765,6 +921,13 @@ func (in *Retry) DeepCopyInto(out *Retry) {
 	*out = *in
 	out.Exponential = in.Exponential
 	out.Constant = in.Constant
+	out.Jitter = in.Jitter
+	out.MaxDuration = in.MaxDuration
+	if in.RetryOn != nil {
+		in, out := &in.RetryOn, &out.RetryOn
+		*out = new(RetryOn)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Retry.
@@ -777,6 +940,26 @@ func (in *Retry) DeepCopy() *Retry {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryOn) DeepCopyInto(out *RetryOn) {
+	*out = *in
+	if in.ExitCodes != nil {
+		in, out := &in.ExitCodes, &out.ExitCodes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryOn.
+func (in *RetryOn) DeepCopy() *RetryOn {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryOn)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RunStep) DeepCopyInto(out *RunStep) {
 	*out = *in
@@ -813,6 +996,21 @@ func (in *SecretVar) DeepCopy() *SecretVar {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SemaphoreRef) DeepCopyInto(out *SemaphoreRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SemaphoreRef.
+func (in *SemaphoreRef) DeepCopy() *SemaphoreRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SemaphoreRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Source) DeepCopyInto(out *Source) {
 	*out = *in
@@ -857,6 +1055,21 @@ func (in *Step) DeepCopyInto(out *Step) {
 		*out = new(InheritStep)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		*out = new(ApprovalStep)
+		**out = **in
+	}
+	if in.Switch != nil {
+		in, out := &in.Switch, &out.Switch
+		*out = new(SwitchStep)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ForEach != nil {
+		in, out := &in.ForEach, &out.ForEach
+		*out = new(ForEachStep)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Step.
@@ -877,6 +1090,13 @@ func (in *StepOptions) DeepCopyInto(out *StepOptions) {
 		*out = new(StepReference)
 		**out = **in
 	}
+	if in.With != nil {
+		in, out := &in.With, &out.With
+		*out = make([]Param, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.If != nil {
 		in, out := &in.If, &out.If
 		*out = make([]IfCondition, len(*in))
@@ -892,6 +1112,11 @@ func (in *StepOptions) DeepCopyInto(out *StepOptions) {
 		}
 	}
 	out.Timeout = in.Timeout
+	if in.AllowFailure != nil {
+		in, out := &in.AllowFailure, &out.AllowFailure
+		*out = new(AllowFailure)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Template != nil {
 		in, out := &in.Template, &out.Template
 		*out = new(Template)
@@ -930,7 +1155,7 @@ func (in *StepOptions) DeepCopyInto(out *StepOptions) {
 	if in.Retry != nil {
 		in, out := &in.Retry, &out.Retry
 		*out = new(Retry)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Secrets != nil {
 		in, out := &in.Secrets, &out.Secrets
@@ -951,6 +1176,11 @@ func (in *StepOptions) DeepCopyInto(out *StepOptions) {
 		*out = make([]Tag, len(*in))
 		copy(*out, *in)
 	}
+	if in.Semaphores != nil {
+		in, out := &in.Semaphores, &out.Semaphores
+		*out = make([]SemaphoreRef, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepOptions.
@@ -979,6 +1209,115 @@ func (in *StepOutputParam) DeepCopy() *StepOutputParam {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepPackage) DeepCopyInto(out *StepPackage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.StepPackageSpec.DeepCopyInto(&out.StepPackageSpec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepPackage.
+func (in *StepPackage) DeepCopy() *StepPackage {
+	if in == nil {
+		return nil
+	}
+	out := new(StepPackage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StepPackage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepPackageList) DeepCopyInto(out *StepPackageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StepPackage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepPackageList.
+func (in *StepPackageList) DeepCopy() *StepPackageList {
+	if in == nil {
+		return nil
+	}
+	out := new(StepPackageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StepPackageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepPackageSpec) DeepCopyInto(out *StepPackageSpec) {
+	*out = *in
+	if in.Inputs != nil {
+		in, out := &in.Inputs, &out.Inputs
+		*out = make(InputParams, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]StepOutputParam, len(*in))
+		copy(*out, *in)
+	}
+	in.Step.DeepCopyInto(&out.Step)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepPackageSpec.
+func (in *StepPackageSpec) DeepCopy() *StepPackageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StepPackageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepPatch) DeepCopyInto(out *StepPatch) {
+	*out = *in
+	out.Step = in.Step
+	if in.Patch != nil {
+		in, out := &in.Patch, &out.Patch
+		*out = make([]JSONPatchOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepPatch.
+func (in *StepPatch) DeepCopy() *StepPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(StepPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StepReference) DeepCopyInto(out *StepReference) {
 	*out = *in
@@ -1004,6 +1343,20 @@ func (in *StepResult) DeepCopyInto(out *StepResult) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Envs != nil {
+		in, out := &in.Envs, &out.Envs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepResult.
@@ -1061,6 +1414,73 @@ func (in *Streams) DeepCopy() *Streams {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchCase) DeepCopyInto(out *SwitchCase) {
+	*out = *in
+	if in.Refs != nil {
+		in, out := &in.Refs, &out.Refs
+		*out = make([]StepReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchCase.
+func (in *SwitchCase) DeepCopy() *SwitchCase {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchCase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchDefault) DeepCopyInto(out *SwitchDefault) {
+	*out = *in
+	if in.Refs != nil {
+		in, out := &in.Refs, &out.Refs
+		*out = make([]StepReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchDefault.
+func (in *SwitchDefault) DeepCopy() *SwitchDefault {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchDefault)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchStep) DeepCopyInto(out *SwitchStep) {
+	*out = *in
+	if in.Cases != nil {
+		in, out := &in.Cases, &out.Cases
+		*out = make([]SwitchCase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(SwitchDefault)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchStep.
+func (in *SwitchStep) DeepCopy() *SwitchStep {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Tag) DeepCopyInto(out *Tag) {
 	*out = *in
@@ -30,6 +30,13 @@ type StoreSpec struct {
 
 type App struct {
 	Name        string      `json:"name,omitempty"`
+	Alias       string      `json:"alias,omitempty"`
+	Source      string      `json:"source,omitempty"`
+	Revision    string      `json:"revision,omitempty"`
+	ETag        string      `json:"etag,omitempty"`
+	Digest      string      `json:"digest,omitempty"`
+	Tag         string      `json:"tag,omitempty"`
+	Verified    bool        `json:"verified,omitempty"`
 	InstalledAt metav1.Time `json:"installedAt,omitempty"`
 	Manifest    []byte      `json:"manifest,omitempty"`
 }
@@ -0,0 +1,47 @@
+/*
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+type TrustPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+	TrustPolicySpec `json:",inline"`
+}
+
+type TrustPolicySpec struct {
+	Rules []TrustRule `json:"rules,omitempty"`
+}
+
+// TrustRule maps a repository glob (e.g. ghcr.io/org/*) to the public keys which are accepted as signers.
+type TrustRule struct {
+	Match      string   `json:"match,omitempty"`
+	PublicKeys []string `json:"publicKeys,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// TrustPolicyList contains a list of TrustPolicy
+type TrustPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TrustPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TrustPolicy{}, &TrustPolicyList{})
+}